@@ -0,0 +1,305 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/agecrypt"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/fingerprint"
+	"github.com/idelchi/gogen/pkg/printer"
+)
+
+// NewAgeCommand creates the age subcommand for age key and file operations.
+// It groups the commands for generating age identities and encrypting and decrypting age files.
+func NewAgeCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "age",
+		Short: "Work with age keys and files",
+		Long: "Generate age X25519 identities and encrypt or decrypt files in the age format,\n" +
+			"compatible with age and age-keygen (https://age-encryption.org).",
+		RunE: cobraext.UnknownSubcommandAction,
+	}
+
+	cmd.AddCommand(
+		newAgeKeygenCommand(cfg),
+		newAgeRecipientCommand(cfg),
+		newAgeEncryptCommand(cfg),
+		newAgeDecryptCommand(cfg),
+	)
+
+	return cmd
+}
+
+// newAgeKeygenCommand creates the age keygen subcommand.
+// It generates an identity file, like age-keygen.
+func newAgeKeygenCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate an age identity",
+		Long: "Generate an age X25519 identity file (AGE-SECRET-KEY-1...) with its recipient (age1...) as comment.\n" +
+			"When writing to a file, the recipient is also printed to stderr. Existing files are not overwritten.\n" +
+			"With --fingerprint, the fingerprint of the X25519 public key is printed to stderr.",
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.Age)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			identity, err := agecrypt.Generate()
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			// The fingerprint is computed first, to not leave an identity file behind on errors.
+			var identifier string
+
+			if cfg.Age.Fingerprint != "" {
+				public, err := identity.PublicKey()
+				if err != nil {
+					return err //nolint:wrapcheck	// Error does not need additional wrapping.
+				}
+
+				identifier, err = fingerprint.Of(public, fingerprint.Algorithm(cfg.Age.Fingerprint))
+				if err != nil {
+					return fmt.Errorf("%w: %w", config.ErrUsage, err)
+				}
+			}
+
+			if err := writeNewOutput(cfg.Age.Out, func(writer io.Writer) error {
+				_, err := io.WriteString(writer, identity.File(time.Now()))
+
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}); err != nil {
+				return err
+			}
+
+			if cfg.Age.Out != "-" {
+				printer.Stderrln("Public key: %s", identity.Recipient)
+			}
+
+			if identifier != "" {
+				printer.Stderrln("Fingerprint: %s", identifier)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "-", "File to write the identity to, '-' for stdout")
+	cmd.Flags().String("fingerprint", "", "Print the fingerprint of the public key to stderr (sha256, jwk)")
+	cmd.Flags().Lookup("fingerprint").NoOptDefVal = string(fingerprint.SHA256)
+
+	return cmd
+}
+
+// newAgeRecipientCommand creates the age recipient subcommand.
+// It converts identity files to recipients, like age-keygen -y.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newAgeRecipientCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipient [identity-file|STDIN]",
+		Short: "Print the recipients of an age identity file",
+		Long:  "Print the recipient (age1...) of every identity in an identity file.",
+		Args:  cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Age.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Age)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			input, err := openInput(cfg.Age.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			recipients, err := agecrypt.Recipients(input)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			fmt.Println(strings.Join(recipients, "\n"))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// newAgeEncryptCommand creates the age encrypt subcommand.
+// It encrypts files to recipients or a passphrase.
+func newAgeEncryptCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt [flags] [file|STDIN]",
+		Short: "Encrypt a file with age",
+		Long:  "Encrypt a file or stdin to age recipients, or to a passphrase (scrypt).",
+		Args:  cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Age.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Age)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			recipients, err := ageRecipients(cfg)
+			if err != nil {
+				return err
+			}
+
+			input, err := openInput(cfg.Age.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			return writeOutput(cfg.Age.Out, func(writer io.Writer) error {
+				//nolint:wrapcheck	// Error does not need additional wrapping.
+				return agecrypt.Encrypt(writer, input, cfg.Age.Armor, recipients...)
+			})
+		},
+	}
+
+	cmd.Flags().StringArrayP("recipient", "r", nil, "Recipient (age1...) to encrypt to, can be repeated")
+	cmd.Flags().StringArrayP("recipients-file", "R", nil, "File with one recipient per line, can be repeated")
+	cmd.Flags().BoolP("passphrase", "p", false, "Encrypt to a prompted passphrase instead of recipients")
+	cmd.Flags().String("passphrase-file", "", "File containing the passphrase (first line)")
+	cmd.Flags().BoolP("armor", "a", false, "Write ASCII-armored (PEM) output")
+	cmd.Flags().StringP("output", "o", "-", "File to write the result to, '-' for stdout")
+
+	return cmd
+}
+
+// agePassphrase returns the passphrase settings of the age configuration.
+func agePassphrase(cfg config.Age) config.Encrypt {
+	return config.Encrypt{Input: cfg.Input, PassphraseFile: cfg.PassphraseFile}
+}
+
+// ageRecipients returns the configured recipients, or the scrypt recipient of the passphrase.
+func ageRecipients(cfg *config.Config) ([]age.Recipient, error) {
+	if cfg.Age.Passphrase || cfg.Age.PassphraseFile != "" {
+		if len(cfg.Age.Recipients) > 0 || len(cfg.Age.RecipientsFiles) > 0 {
+			return nil, fmt.Errorf("%w: a passphrase cannot be combined with recipients", config.ErrUsage)
+		}
+
+		passphrase, err := readPassphrase(agePassphrase(cfg.Age), true)
+		if err != nil {
+			return nil, err
+		}
+
+		recipient, err := agecrypt.Passphrase(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+		}
+
+		return []age.Recipient{recipient}, nil
+	}
+
+	files := make([]io.Reader, 0, len(cfg.Age.RecipientsFiles))
+
+	for _, path := range cfg.Age.RecipientsFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading recipients file: %w", err)
+		}
+
+		files = append(files, strings.NewReader(string(content)))
+	}
+
+	recipients, err := agecrypt.ParseRecipients(cfg.Age.Recipients, files...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w (use --recipient, --recipients-file or --passphrase)", config.ErrUsage, err)
+	}
+
+	return recipients, nil
+}
+
+// newAgeDecryptCommand creates the age decrypt subcommand.
+// It decrypts age files with identity files or a passphrase.
+func newAgeDecryptCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt [flags] [file|STDIN]",
+		Short: "Decrypt an age file",
+		Long: "Decrypt an age file or stdin with identity files, or with a passphrase if it was encrypted to one.\n" +
+			"ASCII-armored input is detected automatically.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Age.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Age)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			input, err := openInput(cfg.Age.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			decryptor := agecrypt.NewDecryptor(input)
+
+			var reader io.Reader
+
+			if decryptor.NeedsPassphrase() {
+				if len(cfg.Age.Identities) > 0 {
+					return fmt.Errorf("%w: the file is encrypted to a passphrase, not to identities", config.ErrUsage)
+				}
+
+				passphrase, err := readPassphrase(agePassphrase(cfg.Age), false)
+				if err != nil {
+					return err
+				}
+
+				reader, err = decryptor.DecryptPassphrase(passphrase)
+				if err != nil {
+					return err //nolint:wrapcheck	// Error does not need additional wrapping.
+				}
+			} else {
+				if len(cfg.Age.Identities) == 0 {
+					return fmt.Errorf("%w: the file is encrypted to recipients, use --identity", config.ErrUsage)
+				}
+
+				if reader, err = ageDecrypt(decryptor, cfg.Age.Identities); err != nil {
+					return err
+				}
+			}
+
+			return writeOutput(cfg.Age.Out, func(writer io.Writer) error {
+				if _, err := io.Copy(writer, reader); err != nil {
+					return fmt.Errorf("decrypting: %w", err)
+				}
+
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringArrayP("identity", "i", nil, "Identity file to decrypt with, can be repeated")
+	cmd.Flags().String("passphrase-file", "", "File containing the passphrase (first line)")
+	cmd.Flags().StringP("output", "o", "-", "File to write the result to, '-' for stdout")
+
+	return cmd
+}
+
+// ageDecrypt decrypts with the identities in the identity files at the paths.
+func ageDecrypt(decryptor *agecrypt.Decryptor, paths []string) (io.Reader, error) {
+	files := make([]io.Reader, 0, len(paths))
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening identity file: %w", err)
+		}
+		defer file.Close()
+
+		files = append(files, file)
+	}
+
+	return decryptor.Decrypt(files...) //nolint:wrapcheck	// Error does not need additional wrapping.
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/keyformat"
+)
+
+// NewConvertCommand creates the key format conversion subcommand.
+// It handles converting asymmetric keys between formats and extracting public keys.
+func NewConvertCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert [flags] [file|STDIN]",
+		Short: "Convert a key between formats",
+		Long: "Convert an RSA, ECDSA, Ed25519 or X25519 key to another format, detecting the input format.\n" +
+			"The input is PEM, DER, an OpenSSH public key or a JWK. Encrypted PKCS#8, OpenSSH and legacy PEM keys\n" +
+			"are decrypted with a prompted passphrase or --passphrase-file.\n\n" +
+			"Formats:\n" +
+			"  pkcs1    RSA PRIVATE KEY / RSA PUBLIC KEY (RSA only)\n" +
+			"  pkcs8    PRIVATE KEY, or ENCRYPTED PRIVATE KEY (PBES2, PBKDF2-SHA256, AES-256-CBC)\n" +
+			"  sec1     EC PRIVATE KEY (ECDSA only)\n" +
+			"  pkix     PUBLIC KEY, always the public key\n" +
+			"  openssh  OPENSSH PRIVATE KEY, or authorized_keys line for public keys\n" +
+			"  jwk      JSON Web Key",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Convert.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Convert)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			input, err := openInput(cfg.Convert.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			data, err := io.ReadAll(input)
+			if err != nil {
+				return fmt.Errorf("reading key: %w", err)
+			}
+
+			parsed, _, err := keyformat.Decode(data, func() ([]byte, error) {
+				passphrase, err := readPassphrase(
+					config.Encrypt{Input: cfg.Convert.Input, PassphraseFile: cfg.Convert.PassphraseFile}, false)
+
+				return []byte(passphrase), err
+			})
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			if cfg.Convert.Public {
+				parsed = keyformat.Public(parsed)
+			}
+
+			options := keyformat.Options{Comment: cfg.Convert.Comment}
+
+			if cfg.Convert.Encrypt || cfg.Convert.NewPassphraseFile != "" {
+				passphrase, err := readPassphrase(
+					config.Encrypt{Input: cfg.Convert.Input, PassphraseFile: cfg.Convert.NewPassphraseFile}, true)
+				if err != nil {
+					return err
+				}
+
+				options.Encrypt = true
+				options.Passphrase = []byte(passphrase)
+			}
+
+			encoded, err := keyformat.Encode(parsed, keyformat.Format(cfg.Convert.To), options)
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			return writeOutput(cfg.Convert.Out, func(writer io.Writer) error {
+				_, err := writer.Write(encoded)
+
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			})
+		},
+	}
+
+	cmd.Flags().StringP("to", "t", "", "Format to convert to (pkcs1, pkcs8, sec1, pkix, openssh, jwk)")
+	cmd.Flags().BoolP("public", "P", false, "Write the public key of a private key")
+	cmd.Flags().String("passphrase-file", "", "File containing the passphrase of an encrypted input key (first line)")
+	cmd.Flags().Bool("encrypt", false, "Encrypt the output key with a prompted passphrase (pkcs8, openssh)")
+	cmd.Flags().String("new-passphrase-file", "", "File containing the passphrase to encrypt the output key with")
+	cmd.Flags().StringP("comment", "c", "", "Comment of OpenSSH keys")
+	cmd.Flags().StringP("output", "o", "-", "File to write the converted key to, '-' for stdout")
+
+	return cmd
+}
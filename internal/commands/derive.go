@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/argon"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/kdf"
+	"github.com/idelchi/gogen/pkg/key"
+)
+
+// deriveFlags lists the flags that only apply to some key derivation functions.
+//
+//nolint:gochecknoglobals	// Constant lookup table.
+var deriveFlags = []struct {
+	flag       string
+	algorithms []string
+}{
+	{"key", []string{"hkdf-sha256", "hkdf-sha512"}},
+	{"key-file", []string{"hkdf-sha256", "hkdf-sha512"}},
+	{"key-encoding", []string{"hkdf-sha256", "hkdf-sha512"}},
+	{"info", []string{"hkdf-sha256", "hkdf-sha512"}},
+	{"passphrase-file", []string{"argon2id", "scrypt"}},
+	{"iterations", []string{"argon2id"}},
+	{"memory", []string{"argon2id"}},
+	{"parallelism", []string{"argon2id"}},
+	{"log-n", []string{"scrypt"}},
+}
+
+// NewDeriveCommand creates the key derivation subcommand.
+// It handles deriving subkeys from a master key, and keys from passphrases.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func NewDeriveCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "derive",
+		Short: "Derive a key from a master key or passphrase",
+		Long: "Derive a key deterministically, so per-service subkeys need not be stored.\n\n" +
+			"Algorithms:\n" +
+			"  hkdf-sha256, hkdf-sha512  subkey of a master key bound to --info (RFC 5869)\n" +
+			"  argon2id, scrypt          key of a passphrase and a --salt (scrypt uses r=8, p=1)\n\n" +
+			"The master key is given as flag, environment variable (GOGEN_KEY) or file.\n" +
+			"The passphrase is prompted for, or read from --passphrase-file.",
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.Derive)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, applicable := range deriveFlags {
+				if cmd.Flags().Changed(applicable.flag) && !slices.Contains(applicable.algorithms, cfg.Derive.Algorithm) {
+					return fmt.Errorf("%w: --%s requires --algorithm %s",
+						config.ErrUsage, applicable.flag, strings.Join(applicable.algorithms, " or "))
+				}
+			}
+
+			derived, err := deriveKey(cfg.Derive)
+			if err != nil {
+				return err
+			}
+
+			encoded, err := derived.Encode(cfg.Derive.Encoding)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			fmt.Print(encoded)
+
+			return nil
+		},
+	}
+
+	const length = 32
+
+	cmd.Flags().StringP("algorithm", "a", "hkdf-sha256", "Key derivation function (hkdf-sha256, hkdf-sha512, argon2id, scrypt)")
+	cmd.Flags().StringP("key", "k", "", "Master key, prefer GOGEN_KEY or --key-file to keep it out of the shell history")
+	cmd.Flags().String("key-file", "", "File containing the master key")
+	cmd.Flags().String("key-encoding", "hex", "Encoding of the master key (hex, base64, base64url, base32, mnemonic, raw)")
+	cmd.Flags().String("passphrase-file", "", "File containing the passphrase (first line)")
+	cmd.Flags().StringP("info", "i", "", "Context the key is bound to, e.g. the name of a service")
+	cmd.Flags().StringP("salt", "s", "", "Salt of the derivation, optional for hkdf")
+	cmd.Flags().IntP("length", "l", length, "Length of the derived key in bytes")
+	cmd.Flags().StringP("encoding", "e", "hex", "Encoding of the derived key (hex, base64, base64url, base32, mnemonic)")
+	cmd.Flags().Uint32("iterations", argon.DefaultParams.Iterations, "Number of passes of argon2id")
+	cmd.Flags().Uint32("memory", argon.DefaultParams.Memory, "Memory of argon2id in KiB")
+	cmd.Flags().Uint8("parallelism", argon.DefaultParams.Parallelism, "Number of lanes of argon2id")
+	cmd.Flags().Int("log-n", kdf.DefaultScryptParams.LogN, "Base-2 logarithm of the CPU/memory cost of scrypt")
+
+	return cmd
+}
+
+// deriveKey derives the key with the configured algorithm.
+func deriveKey(cfg config.Derive) (key.Key, error) {
+	var (
+		derived key.Key
+		err     error
+	)
+
+	switch cfg.Algorithm {
+	case "argon2id", "scrypt":
+		var passphrase string
+
+		passphrase, err = readPassphrase(config.Encrypt{PassphraseFile: cfg.PassphraseFile}, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Algorithm == "argon2id" {
+			params := argon.Params{Iterations: cfg.Iterations, Memory: cfg.Memory, Parallelism: cfg.Parallelism}
+			derived, err = kdf.Argon2id([]byte(passphrase), []byte(cfg.Salt), cfg.Length, params)
+		} else {
+			params := kdf.DefaultScryptParams
+			params.LogN = cfg.LogN
+			derived, err = kdf.Scrypt([]byte(passphrase), []byte(cfg.Salt), cfg.Length, params)
+		}
+	default:
+		var master key.Key
+
+		master, err = readKey(cfg.Key, cfg.KeyFile, cfg.KeyEncoding)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := kdf.Hash(strings.TrimPrefix(cfg.Algorithm, "hkdf-"))
+		derived, err = kdf.HKDF(master, []byte(cfg.Salt), []byte(cfg.Info), cfg.Length, hash)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+	}
+
+	return derived, nil
+}
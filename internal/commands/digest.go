@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/digest"
+	"github.com/idelchi/gogen/pkg/printer"
+)
+
+// errChecksum indicates files that failed checksum verification.
+var errChecksum = errors.New("checksum verification failed")
+
+// NewDigestCommand creates the digest subcommand.
+// It handles computing file digests and verifying checksum files.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func NewDigestCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest [flags] [file...|STDIN]",
+		Short: "Compute or check file digests",
+		Long: "Compute the digests of files or stdin, in the format of sha256sum.\n" +
+			"Files are streamed and hashed in parallel.\n\n" +
+			"With --check, the arguments are checksum files whose listed digests are verified.",
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Digest.Files = args
+
+			if len(args) == 0 {
+				cfg.Digest.Files = []string{digest.Stdin}
+			}
+
+			return cobraext.Validate(cfg, &cfg.Digest)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			algorithm := digest.Algorithm(cfg.Digest.Algorithm)
+
+			if cfg.Digest.Check {
+				return checkDigests(cfg.Digest, algorithm)
+			}
+
+			if err := requires(cmd, "check", "quiet", "ignore-missing"); err != nil {
+				return err
+			}
+
+			var failed int
+
+			for _, sum := range digest.Files(cfg.Digest.Files, algorithm, cfg.Digest.Jobs) {
+				if sum.Err != nil {
+					printer.Stderrln("%s: %v", sum.Path, sum.Err)
+
+					failed++
+
+					continue
+				}
+
+				fmt.Print(sum)
+			}
+
+			if failed > 0 {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return fmt.Errorf("%d of %d files could not be hashed", failed, len(cfg.Digest.Files))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("algorithm", "a", string(digest.SHA256), fmt.Sprintf("Digest algorithm %v", digest.Algorithms()))
+	cmd.Flags().BoolP("check", "c", false, "Verify the digests listed in the checksum files")
+	cmd.Flags().BoolP("quiet", "q", false, "Do not print OK for successfully verified files")
+	cmd.Flags().Bool("ignore-missing", false, "Skip missing files when checking")
+	cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of files hashed in parallel")
+
+	return cmd
+}
+
+// checkDigests verifies the digests listed in the checksum files, reporting like sha256sum --check.
+//
+//nolint:forbidigo	// Function prints out to the console.
+func checkDigests(cfg config.Digest, algorithm digest.Algorithm) error {
+	var entries []digest.Entry
+
+	for _, path := range cfg.Files {
+		listed, err := readCheckFile(path, algorithm)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, listed...)
+	}
+
+	paths := make([]string, len(entries))
+	for index, entry := range entries {
+		paths[index] = entry.Path
+	}
+
+	var mismatched, unreadable, verified int
+
+	for index, sum := range digest.Files(paths, algorithm, cfg.Jobs) {
+		switch {
+		case sum.Err != nil && cfg.IgnoreMissing && errors.Is(sum.Err, fs.ErrNotExist):
+			continue
+		case sum.Err != nil:
+			printer.Stderrln("%s: %v", sum.Path, sum.Err)
+			fmt.Printf("%s: FAILED open or read\n", sum.Path)
+
+			unreadable++
+		case sum.Digest != entries[index].Digest:
+			fmt.Printf("%s: FAILED\n", sum.Path)
+
+			mismatched++
+		default:
+			if !cfg.Quiet {
+				fmt.Printf("%s: OK\n", sum.Path)
+			}
+
+			verified++
+		}
+	}
+
+	if unreadable > 0 {
+		printer.Stderrln("WARNING: %d listed files could not be read", unreadable)
+	}
+
+	if mismatched > 0 {
+		printer.Stderrln("WARNING: %d computed checksums did NOT match", mismatched)
+	}
+
+	if mismatched > 0 || unreadable > 0 {
+		return fmt.Errorf("%w: %d of %d files", errChecksum, mismatched+unreadable, len(entries))
+	}
+
+	if verified == 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return errors.New("no file was verified")
+	}
+
+	return nil
+}
+
+// readCheckFile reads the entries of the checksum file at the path, or of stdin if the path is "-".
+func readCheckFile(path string, algorithm digest.Algorithm) ([]digest.Entry, error) {
+	if path == digest.Stdin {
+		entries, err := digest.ParseCheckFile(os.Stdin, algorithm)
+		if err != nil {
+			return nil, fmt.Errorf("parsing checksums from stdin: %w", err)
+		}
+
+		return entries, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening checksum file: %w", err)
+	}
+	defer file.Close()
+
+	entries, err := digest.ParseCheckFile(file, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("parsing checksum file %q: %w", path, err)
+	}
+
+	return entries, nil
+}
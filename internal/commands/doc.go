@@ -1,7 +1,21 @@
 // Package commands provides the command-line interface for the gogen tool.
 //
 // It implements commands for:
-//   - Random password generation
+//   - Random and derived password generation
+//   - Diceware passphrase generation
+//   - Web framework secret generation (Django, Rails, Laravel, Fernet, Flask, NextAuth)
+//   - Filling .env files with generated secrets
+//   - Numeric PIN generation
+//   - Unique identifier (UUID, ULID, KSUID, NanoID, Snowflake) generation
 //   - Password hashing with bcrypt
-//   - Cryptographic key generation
+//   - File digests and checksum file verification
+//   - HMAC signing and verification, including webhook signature headers
+//   - Authenticated file encryption with keys or passphrases
+//   - age identities and age file encryption
+//   - Shamir secret sharing of secrets among custodians
+//   - WireGuard key pairs, preshared keys and configuration snippets
+//   - Cryptographic key generation and derivation, and mnemonic key backups
+//   - Key fingerprints (truncated SHA-256, OpenSSH, JWK thumbprints)
+//   - Key format conversion (PKCS#1, PKCS#8, SEC1, PKIX, OpenSSH, JWK)
+//   - One-time password (HOTP/TOTP) secrets
 package commands
@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/argon"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/crypt"
+)
+
+// NewEncryptCommand creates the encrypt subcommand.
+// It handles encrypting files or stdin with a key or passphrase.
+func NewEncryptCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt [flags] [file|STDIN]",
+		Short: "Encrypt a file",
+		Long: "Encrypt a file or stdin with AES-256-GCM or XChaCha20-Poly1305.\n" +
+			"The key is a 32-byte key, e.g. from 'gogen key', or derived from a passphrase with Argon2id.\n" +
+			"Files of any size are encrypted in chunks, in constant memory.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Encrypt.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Encrypt)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cipher, err := crypt.ParseCipher(cfg.Encrypt.Cipher)
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			newWriter := func(destination io.Writer) (*crypt.Writer, error) {
+				if !usesPassphrase(cfg.Encrypt) {
+					secret, err := readKey(cfg.Encrypt.Key, cfg.Encrypt.KeyFile, cfg.Encrypt.KeyEncoding)
+					if err != nil {
+						return nil, err
+					}
+
+					return crypt.NewWriter(destination, secret, cipher) //nolint:wrapcheck	// Error does not need additional wrapping.
+				}
+
+				passphrase, err := readPassphrase(cfg.Encrypt, true)
+				if err != nil {
+					return nil, err
+				}
+
+				//nolint:wrapcheck	// Error does not need additional wrapping.
+				return crypt.NewPassphraseWriter(destination, []byte(passphrase), cipher, argon.DefaultParams)
+			}
+
+			if err := exclusiveSecrets(cfg.Encrypt); err != nil {
+				return err
+			}
+
+			input, err := openInput(cfg.Encrypt.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			return writeOutput(cfg.Encrypt.Out, func(destination io.Writer) error {
+				writer, err := newWriter(destination)
+				if err != nil {
+					return err
+				}
+
+				if _, err := io.Copy(writer, input); err != nil {
+					return fmt.Errorf("encrypting: %w", err)
+				}
+
+				return writer.Close()
+			})
+		},
+	}
+
+	cmd.Flags().String("cipher", crypt.AES256GCM.String(), "Cipher to encrypt with (aes-256-gcm, xchacha20-poly1305)")
+	addSecretFlags(cmd)
+
+	return cmd
+}
+
+// NewDecryptCommand creates the decrypt subcommand.
+// It handles decrypting files or stdin encrypted by the encrypt subcommand.
+func NewDecryptCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt [flags] [file|STDIN]",
+		Short: "Decrypt a file",
+		Long: "Decrypt a file or stdin encrypted with 'gogen encrypt'.\n" +
+			"The cipher and key derivation are read from the file; a passphrase is prompted for if needed.\n" +
+			"When writing to a file, it is only created once the whole input was authenticated.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Encrypt.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Encrypt)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := exclusiveSecrets(cfg.Encrypt); err != nil {
+				return err
+			}
+
+			input, err := openInput(cfg.Encrypt.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			header, err := crypt.ReadHeader(input)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			var secret []byte
+
+			switch {
+			case header.NeedsPassphrase():
+				if cfg.Encrypt.Key != "" || cfg.Encrypt.KeyFile != "" {
+					return fmt.Errorf("%w: the file is encrypted with a passphrase, not a key", config.ErrUsage)
+				}
+
+				passphrase, err := readPassphrase(cfg.Encrypt, false)
+				if err != nil {
+					return err
+				}
+
+				secret = []byte(passphrase)
+			case usesPassphrase(cfg.Encrypt):
+				return fmt.Errorf("%w: the file is encrypted with a key, not a passphrase", config.ErrUsage)
+			default:
+				if secret, err = readKey(cfg.Encrypt.Key, cfg.Encrypt.KeyFile, cfg.Encrypt.KeyEncoding); err != nil {
+					return err
+				}
+			}
+
+			reader, err := crypt.NewReader(input, header, secret)
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			return writeOutput(cfg.Encrypt.Out, func(destination io.Writer) error {
+				if _, err := io.Copy(destination, reader); err != nil {
+					return fmt.Errorf("decrypting: %w", err)
+				}
+
+				return nil
+			})
+		},
+	}
+
+	addSecretFlags(cmd)
+
+	return cmd
+}
+
+// addSecretFlags registers the flags for the key or passphrase and the output of encryption commands.
+func addSecretFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("key", "k", "", "32-byte key, prefer GOGEN_KEY or --key-file to keep it out of the shell history")
+	cmd.Flags().String("key-file", "", "File containing the key")
+	cmd.Flags().String("key-encoding", "hex", "Encoding of the key (hex, base64, raw)")
+	cmd.Flags().BoolP("passphrase", "p", false, "Use a prompted passphrase instead of a key")
+	cmd.Flags().String("passphrase-file", "", "File containing the passphrase (first line)")
+	cmd.Flags().StringP("output", "o", "-", "File to write the result to, '-' for stdout")
+}
+
+// exclusiveSecrets returns a usage error if both a key and a passphrase were given.
+func exclusiveSecrets(cfg config.Encrypt) error {
+	if usesPassphrase(cfg) && (cfg.Key != "" || cfg.KeyFile != "") {
+		return fmt.Errorf("%w: a key and a passphrase cannot be combined", config.ErrUsage)
+	}
+
+	return nil
+}
+
+// usesPassphrase reports whether a passphrase was requested instead of a key.
+func usesPassphrase(cfg config.Encrypt) bool {
+	return cfg.Passphrase || cfg.PassphraseFile != ""
+}
@@ -0,0 +1,247 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/dotenv"
+	"github.com/idelchi/gogen/pkg/key"
+	"github.com/idelchi/gogen/pkg/printer"
+	"github.com/idelchi/gogen/pkg/pw"
+	"github.com/idelchi/gogen/pkg/secret"
+	"github.com/idelchi/gogen/pkg/uid"
+)
+
+// placeholder matches a placeholder such as ${gogen:pw:24}, capturing its spec.
+var placeholder = regexp.MustCompile(`^\$\{gogen:([^}]+)\}$`) //nolint:gochecknoglobals	// Compiled once.
+
+// spec matches the spec of a placeholder, capturing its type and optional argument.
+var spec = regexp.MustCompile(`^([a-z0-9]+)(?::([A-Za-z0-9-]+))?$`) //nolint:gochecknoglobals	// Compiled once.
+
+// NewEnvCommand creates the .env subcommand.
+// It handles filling .env files with generated secrets.
+func NewEnvCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env [flags] [file]",
+		Short: "Fill a .env file with generated secrets",
+		Long: "Generate values for the keys of a .env file (default .env) that are empty or hold a placeholder,\n" +
+			"preserving comments and ordering. Existing values are never overwritten, unless --rotate is given,\n" +
+			"which regenerates the values of all keys that are empty or hold a placeholder in the --template.\n" +
+			"Keys of the template missing from the file are appended. The file is written atomically with mode 0600.\n\n" +
+			"Placeholders:\n" +
+			"  ${gogen:pw[:length]}       password (default 24, at most 1024 characters)\n" +
+			"  ${gogen:key[:length]}      hex key (default 32, at most 512 bytes)\n" +
+			"  ${gogen:base64[:length]}   base64 key (default 32, at most 512 bytes)\n" +
+			"  ${gogen:uuid}              UUIDv4\n" +
+			"  ${gogen:secret:framework}  framework secret, see 'gogen secret'",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Env.File = ".env"
+			if len(args) > 0 {
+				cfg.Env.File = args[0]
+			}
+
+			return cobraext.Validate(cfg, &cfg.Env)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.Env.Template == "" {
+				if err := requires(cmd, "template", "rotate"); err != nil {
+					return err
+				}
+			}
+
+			if _, err := generateSpec(cfg.Env.Default); err != nil {
+				return fmt.Errorf("%w: --default: %w", config.ErrUsage, err)
+			}
+
+			file, generated, err := fillEnv(cfg.Env)
+			if err != nil {
+				return err
+			}
+
+			out := cfg.Env.Out
+			if out == "" {
+				out = cfg.Env.File
+			}
+
+			if err := writeOutput(out, file.Write); err != nil {
+				return err
+			}
+
+			for _, name := range generated {
+				printer.Stderrln("generated %s", name)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("template", "t", "", "Template with the keys and placeholders, e.g. .env.example")
+	cmd.Flags().StringP("output", "o", "", "File to write the result to, the .env file if empty, '-' for stdout")
+	cmd.Flags().Bool("rotate", false, "Regenerate the existing values of the keys marked in the template")
+	cmd.Flags().String("default", "key:32", "Placeholder spec for keys with empty values, e.g. pw:32")
+
+	return cmd
+}
+
+// fillEnv reads the .env file and template, and generates the missing values.
+// It returns the filled file and the keys whose values were generated.
+func fillEnv(cfg config.Env) (dotenv.File, []string, error) {
+	file, err := readEnv(cfg.File)
+
+	missing := errors.Is(err, os.ErrNotExist)
+	if err != nil && !(missing && cfg.Template != "") {
+		return dotenv.File{}, nil, err
+	}
+
+	// Specs of the keys marked in the template, which are regenerated when rotating.
+	marked := map[string]string{}
+
+	if cfg.Template != "" {
+		template, err := readEnv(cfg.Template)
+		if err != nil {
+			return dotenv.File{}, nil, err
+		}
+
+		for _, line := range template.Lines {
+			if line.IsAssignment() && (line.Value == "" || placeholder.MatchString(line.Value)) {
+				marked[line.Key] = specOf(line.Value, cfg.Default)
+			}
+
+			if missing || !line.IsAssignment() || file.Lookup(line.Key) != nil {
+				continue
+			}
+
+			file.Lines = append(file.Lines, line)
+		}
+
+		if missing {
+			file = template
+		}
+	}
+
+	var generated []string
+
+	for i := range file.Lines {
+		line := &file.Lines[i]
+		if !line.IsAssignment() {
+			continue
+		}
+
+		spec, rotate := marked[line.Key]
+
+		switch {
+		case line.Value == "" || placeholder.MatchString(line.Value):
+			spec = specOf(line.Value, cfg.Default)
+		case !cfg.Rotate || !rotate:
+			continue
+		}
+
+		value, err := generateSpec(spec)
+		if err != nil {
+			return dotenv.File{}, nil, fmt.Errorf("%w: %s: %w", config.ErrUsage, line.Key, err)
+		}
+
+		line.Set(value)
+
+		generated = append(generated, line.Key)
+	}
+
+	return file, generated, nil
+}
+
+// readEnv parses the .env file at the path.
+func readEnv(path string) (dotenv.File, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return dotenv.File{}, fmt.Errorf("opening .env file: %w", err)
+	}
+	defer reader.Close()
+
+	file, err := dotenv.Parse(reader)
+	if err != nil {
+		return dotenv.File{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// specOf returns the spec of a placeholder value, or the default spec for an empty value.
+func specOf(value, fallback string) string {
+	if match := placeholder.FindStringSubmatch(value); match != nil {
+		return match[1]
+	}
+
+	return fallback
+}
+
+// generateSpec generates a value for a placeholder spec, such as pw:24.
+func generateSpec(value string) (string, error) {
+	match := spec.FindStringSubmatch(value)
+	if match == nil {
+		return "", fmt.Errorf("invalid placeholder spec %q", value) //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	kind, argument := match[1], match[2]
+
+	length := func(fallback, maximum int) (int, error) {
+		if argument == "" {
+			return fallback, nil
+		}
+
+		parsed, err := strconv.Atoi(argument)
+		if err != nil || parsed < 1 || parsed > maximum {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return 0, fmt.Errorf("invalid length %q in placeholder spec %q, must be 1-%d", argument, value, maximum)
+		}
+
+		return parsed, nil
+	}
+
+	const (
+		passwordLength    = 24
+		maxPasswordLength = 1024
+		keyLength         = 32
+		maxKeyLength      = 512
+	)
+
+	switch kind {
+	case "pw":
+		size, err := length(passwordLength, maxPasswordLength)
+		if err != nil {
+			return "", err
+		}
+
+		return pw.Generate(size, true) //nolint:wrapcheck	// Error does not need additional wrapping.
+	case "key", "base64":
+		size, err := length(keyLength, maxKeyLength)
+		if err != nil {
+			return "", err
+		}
+
+		generated, err := key.New(size)
+		if err != nil {
+			return "", err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		if kind == "base64" {
+			return generated.AsBase64(), nil
+		}
+
+		return generated.AsHex(), nil
+	case "uuid":
+		return uid.UUIDv4() //nolint:wrapcheck	// Error does not need additional wrapping.
+	case "secret":
+		return secret.Generate(argument) //nolint:wrapcheck	// Error does not need additional wrapping.
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("unknown placeholder type %q, supported: pw, key, base64, uuid, secret", kind)
+	}
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/fingerprint"
+	"github.com/idelchi/gogen/pkg/key"
+	"github.com/idelchi/gogen/pkg/keyformat"
+)
+
+// newKeyFingerprintCommand creates the key fingerprint subcommand.
+// It handles computing fingerprints of existing symmetric keys and PEM or OpenSSH keys.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newKeyFingerprintCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fingerprint [flags] [file|STDIN]",
+		Short: "Compute the fingerprint of a key",
+		Long: "Compute a short identifier of a key, to confirm the right key is in use without exposing it.\n" +
+			"The key is a symmetric key encoded as given by --from, a PEM file (PKCS#1, PKCS#8, SEC1, PKIX,\n" +
+			"OpenSSH, certificate), an OpenSSH public key or a JWK. Private keys are identified by their public key.\n\n" +
+			"Algorithms:\n" +
+			"  sha256  SHA-256 truncated to 16 bytes in hex, of the key or the PKIX encoding of the public key\n" +
+			"  ssh     OpenSSH SHA256: fingerprint, as shown by ssh-keygen -l (RSA, ECDSA, Ed25519)\n" +
+			"  jwk     RFC 7638 JWK thumbprint",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.Fingerprint.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.Fingerprint)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			input, err := openInput(cfg.Fingerprint.Input)
+			if err != nil {
+				return err
+			}
+			defer input.Close()
+
+			data, err := io.ReadAll(input)
+			if err != nil {
+				return fmt.Errorf("reading key: %w", err)
+			}
+
+			var parsed any
+
+			if keyformat.IsKey(data) {
+				parsed, err = keyformat.Parse(data)
+			} else {
+				parsed, err = key.Decode(strings.TrimSpace(string(data)), cfg.Fingerprint.From)
+			}
+
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			identifier, err := fingerprint.Of(parsed, fingerprint.Algorithm(cfg.Fingerprint.Algorithm))
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			fmt.Print(identifier)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("algorithm", "a", string(fingerprint.SHA256), "Fingerprint algorithm (sha256, ssh, jwk)")
+	cmd.Flags().String("from", "hex", "Encoding of symmetric keys (hex, base64, base64url, base32, mnemonic)")
+
+	return cmd
+}
+
+// printFingerprint prints the fingerprint of the key with the given algorithm, followed by a newline.
+func printFingerprint(writer io.Writer, key any, algorithm string) error {
+	identifier, err := fingerprint.Of(key, fingerprint.Algorithm(algorithm))
+	if err != nil {
+		return fmt.Errorf("%w: %w", config.ErrUsage, err)
+	}
+
+	fmt.Fprintln(writer, identifier)
+
+	return nil
+}
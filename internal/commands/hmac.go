@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/key"
+	"github.com/idelchi/gogen/pkg/signature"
+)
+
+// NewHMACCommand creates the HMAC subcommand.
+// It handles signing payloads and verifying their MACs or signature headers.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func NewHMACCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hmac [flags] [file|STDIN]",
+		Short: "Sign or verify a payload with an HMAC",
+		Long: "Compute the HMAC of a file or stdin, or verify a given MAC in constant time.\n" +
+			"The key is given as flag, environment variable (GOGEN_KEY) or file, encoded as hex, base64 or raw bytes.\n\n" +
+			"Header formats:\n" +
+			"  github  sha256=<hex> (X-Hub-Signature-256)\n" +
+			"  stripe  t=<timestamp>,v1=<hex> over '<timestamp>.<payload>' (Stripe-Signature)",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			cfg.HMAC.Input = inputArg(args)
+
+			return cobraext.Validate(cfg, &cfg.HMAC)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			secret, err := readKey(cfg.HMAC.Key, cfg.HMAC.KeyFile, cfg.HMAC.KeyEncoding)
+			if err != nil {
+				return err
+			}
+
+			payload, err := openInput(cfg.HMAC.Input)
+			if err != nil {
+				return err
+			}
+			defer payload.Close()
+
+			if cfg.HMAC.Verify != "" {
+				if err := exclusive(cmd, "verify", "timestamp"); err != nil {
+					return err
+				}
+
+				if err := verifyHMAC(cfg.HMAC, secret, payload); err != nil {
+					return err
+				}
+
+				fmt.Println("OK")
+
+				return nil
+			}
+
+			if err := requires(cmd, "verify", "tolerance"); err != nil {
+				return err
+			}
+
+			mac, err := signHMAC(cfg.HMAC, secret, payload)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(mac)
+
+			return nil
+		},
+	}
+
+	const tolerance = 5 * time.Minute
+
+	cmd.Flags().StringP("algorithm", "a", string(signature.SHA256), "Hash function of the HMAC (sha256, sha384, sha512)")
+	cmd.Flags().StringP("key", "k", "", "Key of the HMAC, prefer GOGEN_KEY or --key-file to keep it out of the shell history")
+	cmd.Flags().String("key-file", "", "File containing the key of the HMAC")
+	cmd.Flags().String("key-encoding", "hex", "Encoding of the key (hex, base64, raw)")
+	cmd.Flags().String("encoding", "hex", "Encoding of the MAC (hex, base64)")
+	cmd.Flags().StringP("header", "H", "none", "Signature header format (none, github, stripe)")
+	cmd.Flags().String("verify", "", "MAC or signature header to verify instead of printing the MAC")
+	cmd.Flags().Int64("timestamp", 0, "Unix time to sign Stripe-style payloads at (default now)")
+	cmd.Flags().Duration("tolerance", tolerance, "Maximum age of verified Stripe-style signatures, 0 to disable")
+
+	return cmd
+}
+
+// signHMAC computes the MAC of the payload and formats it as configured.
+func signHMAC(cfg config.HMAC, secret key.Key, payload io.Reader) (string, error) {
+	algorithm := signature.Algorithm(cfg.Algorithm)
+
+	switch cfg.Header {
+	case "github":
+		mac, err := signature.MAC(secret, payload, algorithm)
+		if err != nil {
+			return "", fmt.Errorf("computing hmac: %w", err)
+		}
+
+		return signature.GitHub(mac, algorithm), nil
+	case "stripe":
+		if algorithm != signature.SHA256 {
+			return "", fmt.Errorf("%w: stripe signatures use sha256", config.ErrUsage)
+		}
+
+		header := signature.Stripe{Timestamp: cfg.Timestamp}
+		if header.Timestamp == 0 {
+			header.Timestamp = time.Now().Unix()
+		}
+
+		mac, err := signature.MAC(secret, header.Payload(payload), algorithm)
+		if err != nil {
+			return "", fmt.Errorf("computing hmac: %w", err)
+		}
+
+		header.Signatures = [][]byte{mac}
+
+		return header.String(), nil
+	default:
+		mac, err := signature.MAC(secret, payload, algorithm)
+		if err != nil {
+			return "", fmt.Errorf("computing hmac: %w", err)
+		}
+
+		if cfg.Encoding == "base64" {
+			return base64.StdEncoding.EncodeToString(mac), nil
+		}
+
+		return hex.EncodeToString(mac), nil
+	}
+}
+
+// verifyHMAC verifies the MAC or signature header of the configuration against the payload.
+func verifyHMAC(cfg config.HMAC, secret key.Key, payload io.Reader) error {
+	algorithm := signature.Algorithm(cfg.Algorithm)
+
+	switch cfg.Header {
+	case "github":
+		algorithm, expected, err := signature.ParseGitHub(cfg.Verify)
+		if err != nil {
+			return fmt.Errorf("%w: %w", config.ErrUsage, err)
+		}
+
+		mac, err := signature.MAC(secret, payload, algorithm)
+		if err != nil {
+			return fmt.Errorf("computing hmac: %w", err)
+		}
+
+		return signature.Verify(expected, mac) //nolint:wrapcheck	// Error does not need additional wrapping.
+	case "stripe":
+		header, err := signature.ParseStripe(cfg.Verify)
+		if err != nil {
+			return fmt.Errorf("%w: %w", config.ErrUsage, err)
+		}
+
+		mac, err := signature.MAC(secret, header.Payload(payload), signature.SHA256)
+		if err != nil {
+			return fmt.Errorf("computing hmac: %w", err)
+		}
+
+		//nolint:wrapcheck	// Error does not need additional wrapping.
+		return header.Verify(mac, cfg.Tolerance, time.Now())
+	default:
+		decode := key.FromHex
+		if cfg.Encoding == "base64" {
+			decode = key.FromBase64
+		}
+
+		expected, err := decode(cfg.Verify)
+		if err != nil {
+			return fmt.Errorf("%w: decoding mac: %w", config.ErrUsage, err)
+		}
+
+		mac, err := signature.MAC(secret, payload, algorithm)
+		if err != nil {
+			return fmt.Errorf("computing hmac: %w", err)
+		}
+
+		return signature.Verify(expected, mac) //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+}
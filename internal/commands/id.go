@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/uid"
+)
+
+// NewIDCommand creates the identifier generation subcommand.
+// It handles generating UUIDs, ULIDs, KSUIDs, NanoIDs and Snowflake IDs.
+func NewIDCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "id [name]",
+		Short: "Generate a unique identifier",
+		Long: "Generate unique identifiers.\n\n" +
+			"Types:\n" +
+			"  v4         random UUID\n" +
+			"  v7         time-ordered UUID\n" +
+			"  v5, v3     name-based UUID (SHA-1, MD5) of the name in the namespace\n" +
+			"  ulid       lexicographically sortable identifier\n" +
+			"  ksuid      K-sortable identifier\n" +
+			"  nanoid     random identifier from a custom alphabet and size\n" +
+			"  snowflake  64-bit time-ordered integer with a node ID\n\n" +
+			"The name of name-based UUIDs can be passed as argument or piped.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if err := cobraext.Validate(cfg, &cfg.ID, &cfg.Output); err != nil {
+				return err
+			}
+
+			// Only name-based UUIDs take a name, so stdin is left alone for the other types.
+			if cfg.ID.Type != "v5" && cfg.ID.Type != "v3" {
+				if len(args) > 0 {
+					cfg.ID.Name = args[0]
+				}
+
+				return nil
+			}
+
+			name, err := cobraext.PipeOrArg(args)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			cfg.ID.Name = name
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			generate, err := identifier(cmd, cfg.ID)
+			if err != nil {
+				return err
+			}
+
+			return emit(cmd, cfg.Output, func() (string, error) {
+				id, err := generate()
+				if err != nil {
+					return "", err
+				}
+
+				if cfg.ID.NoHyphens {
+					id = strings.ReplaceAll(id, "-", "")
+				}
+
+				if cfg.ID.Upper {
+					id = strings.ToUpper(id)
+				}
+
+				return id, nil
+			})
+		},
+	}
+
+	cmd.Flags().StringP("type", "t", "v4", "Type of identifier (v4, v7, v5, v3, ulid, ksuid, nanoid, snowflake)")
+	cmd.Flags().String("namespace", "dns", "Namespace of name-based UUIDs (dns, url, oid, x500 or a UUID)")
+	cmd.Flags().BoolP("upper", "U", false, "Format UUIDs in uppercase")
+	cmd.Flags().BoolP("no-hyphens", "H", false, "Format UUIDs without hyphens")
+	cmd.Flags().String("alphabet", uid.NanoIDAlphabet, "Characters of NanoIDs")
+	cmd.Flags().Int("size", uid.NanoIDSize, "Length of NanoIDs")
+	cmd.Flags().Int("node", 0, fmt.Sprintf("Node ID of Snowflake IDs (0-%d)", uid.MaxNode))
+	cmd.Flags().Int64("epoch", uid.SnowflakeEpoch, "Epoch of Snowflake IDs in Unix milliseconds")
+	addOutputFlags(cmd)
+
+	return cmd
+}
+
+// idFlags lists the flags that only apply to some types of identifiers.
+//
+//nolint:gochecknoglobals	// Constant lookup table.
+var idFlags = []struct {
+	flag  string
+	types []string
+}{
+	{"upper", []string{"v4", "v7", "v5", "v3"}},
+	{"no-hyphens", []string{"v4", "v7", "v5", "v3"}},
+	{"namespace", []string{"v5", "v3"}},
+	{"alphabet", []string{"nanoid"}},
+	{"size", []string{"nanoid"}},
+	{"node", []string{"snowflake"}},
+	{"epoch", []string{"snowflake"}},
+}
+
+// identifier returns the generator for the configured type of identifier,
+// rejecting flags that do not apply to it.
+func identifier(cmd *cobra.Command, cfg config.ID) (generator, error) {
+	for _, applicable := range idFlags {
+		if cmd.Flags().Changed(applicable.flag) && !slices.Contains(applicable.types, cfg.Type) {
+			return nil, fmt.Errorf("%w: --%s does not apply to %s identifiers", config.ErrUsage, applicable.flag, cfg.Type)
+		}
+	}
+
+	if cfg.Type != "v5" && cfg.Type != "v3" && cfg.Name != "" {
+		return nil, fmt.Errorf("%w: a name is only used by name-based UUIDs (v5, v3)", config.ErrUsage)
+	}
+
+	switch cfg.Type {
+	case "v7":
+		return uid.UUIDv7, nil
+	case "v5", "v3":
+		return nameUUID(cmd, cfg)
+	case "ulid":
+		return func() (string, error) { return uid.ULID(time.Now()) }, nil
+	case "ksuid":
+		return func() (string, error) { return uid.KSUID(time.Now()) }, nil
+	case "nanoid":
+		return func() (string, error) { return uid.NanoID(cfg.Alphabet, cfg.Size) }, nil
+	case "snowflake":
+		snowflake, err := uid.NewSnowflake(cfg.Node, cfg.Epoch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+		}
+
+		return snowflake.Next, nil
+	default:
+		return uid.UUIDv4, nil
+	}
+}
+
+// nameUUID returns the generator of the name-based UUID of the configured name and namespace.
+// Name-based UUIDs are deterministic, so only a single one can be generated.
+func nameUUID(cmd *cobra.Command, cfg config.ID) (generator, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("%w: %s UUIDs require a name", config.ErrUsage, cfg.Type)
+	}
+
+	for _, name := range []string{"count", "names", "names-file"} {
+		if cmd.Flags().Changed(name) {
+			return nil, fmt.Errorf("%w: --%s does not apply to deterministic %s UUIDs", config.ErrUsage, name, cfg.Type)
+		}
+	}
+
+	if _, err := uid.Namespace(cfg.Namespace); err != nil {
+		return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+	}
+
+	uuid := uid.UUIDv5
+	if cfg.Type == "v3" {
+		uuid = uid.UUIDv3
+	}
+
+	return func() (string, error) { return uuid(cfg.Namespace, cfg.Name) }, nil
+}
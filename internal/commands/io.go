@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/key"
+	"github.com/idelchi/gogen/pkg/stdin"
+)
+
+// readKey decodes the key given as value or read from the file at the path.
+// A single trailing newline of a key file is not part of the key, even for raw keys.
+func readKey(value, path, encoding string) (key.Key, error) {
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+
+		value = string(content)
+		if trimmed, found := strings.CutSuffix(value, "\n"); found {
+			value = strings.TrimSuffix(trimmed, "\r")
+		}
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("%w: a key is required (--key, GOGEN_KEY or --key-file)", config.ErrUsage)
+	}
+
+	var (
+		decoded key.Key
+		err     error
+	)
+
+	if encoding == "raw" {
+		decoded = key.Key(value)
+	} else {
+		decoded, err = key.Decode(value, encoding)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding key: %w", config.ErrUsage, err)
+	}
+
+	return decoded, nil
+}
+
+// openInput opens the file at the path, or stdin if the path is "-".
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening input: %w", err)
+	}
+
+	return file, nil
+}
+
+// readPassphrase reads the passphrase from the passphrase file or prompts for it.
+// On a terminal, new passphrases are prompted for twice to catch typos.
+func readPassphrase(cfg config.Encrypt, confirm bool) (string, error) {
+	if cfg.PassphraseFile != "" {
+		content, err := os.ReadFile(cfg.PassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("reading passphrase file: %w", err)
+		}
+
+		passphrase, _, _ := strings.Cut(string(content), "\n")
+
+		return strings.TrimSuffix(passphrase, "\r"), nil
+	}
+
+	if cfg.Input == "-" && stdin.IsPiped() {
+		return "", fmt.Errorf("%w: cannot read both the input and the passphrase from stdin, use --passphrase-file",
+			config.ErrUsage)
+	}
+
+	passphrase, err := stdin.Secret("Passphrase: ")
+	if err != nil {
+		return "", err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	if confirm && !stdin.IsPiped() {
+		repeated, err := stdin.Secret("Confirm passphrase: ")
+		if err != nil {
+			return "", err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		if repeated != passphrase {
+			return "", errors.New("passphrases do not match") //nolint:err113 // Occasional dynamic errors are fine.
+		}
+	}
+
+	return passphrase, nil
+}
+
+// inputArg returns the input file of the arguments, or "-" for stdin.
+func inputArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+
+	return "-"
+}
+
+// writeOutput writes the output produced by write to stdout, or atomically to the file at the path.
+// Files are created with mode 0600 and only replace the destination once write succeeded.
+func writeOutput(path string, write func(io.Writer) error) error {
+	return writeFile(path, write, os.Rename)
+}
+
+// writeNewOutput is like writeOutput, but fails instead of replacing an existing file.
+func writeNewOutput(path string, write func(io.Writer) error) error {
+	exists := fmt.Errorf("%w: %q already exists, refusing to overwrite it", config.ErrUsage, path)
+
+	if path != "" && path != "-" {
+		if _, err := os.Lstat(path); err == nil {
+			return exists
+		}
+	}
+
+	// Linking fails if the file was created in the meantime, unlike renaming.
+	err := writeFile(path, write, os.Link)
+	if errors.Is(err, fs.ErrExist) {
+		return exists
+	}
+
+	return err
+}
+
+// writeFile writes the output produced by write to stdout, or to a temporary file
+// that is moved to the path with place once write succeeded.
+func writeFile(path string, write func(io.Writer) error, place func(from, to string) error) error {
+	if path == "" || path == "-" {
+		return write(os.Stdout)
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("creating output: %w", err)
+	}
+
+	defer os.Remove(temporary.Name())
+
+	if err := write(temporary); err != nil {
+		temporary.Close()
+
+		return err
+	}
+
+	if err := temporary.Close(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+
+	if err := place(temporary.Name(), path); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+
+	return nil
+}
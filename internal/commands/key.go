@@ -2,42 +2,101 @@ package commands
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/idelchi/gogen/internal/config"
 	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/fingerprint"
 	"github.com/idelchi/gogen/pkg/key"
 )
 
 // NewKeyCommand creates the key generation subcommand.
 // It handles generating cryptographic keys of specified length.
-//
-//nolint:forbidigo	// Command prints out to the console.
 func NewKeyCommand(cfg *config.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "key",
 		Short: "Generate a cryptographic key",
-		Long:  "Generate a cryptographic key of specified length",
-		Args:  cobra.NoArgs,
+		Long: "Generate a cryptographic key of specified length, encoded as hex, base64, base64url, base32 or mnemonic.\n" +
+			"Mnemonics are BIP39 English words with a checksum, for backing up 32-byte keys on paper.\n" +
+			"With --fingerprint, the fingerprint of every key is printed to stderr, to identify it without exposing it.",
+		Args: cobra.NoArgs,
 		PreRunE: func(_ *cobra.Command, _ []string) error {
-			return cobraext.Validate(cfg, &cfg.Generate)
+			return cobraext.Validate(cfg, &cfg.Generate, &cfg.Output)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return emit(cmd, cfg.Output, func() (string, error) {
+				key, err := key.New(cfg.Generate.Length)
+				if err != nil {
+					return "", fmt.Errorf("generating key: %w", err)
+				}
+
+				if cfg.Generate.Fingerprint != "" {
+					if err := printFingerprint(os.Stderr, key, cfg.Generate.Fingerprint); err != nil {
+						return "", err
+					}
+				}
+
+				return key.Encode(cfg.Generate.Encoding) //nolint:wrapcheck	// Error does not need additional wrapping.
+			})
+		},
+	}
+
+	const length = 32
+
+	cmd.Flags().IntP("length", "l", length, "Length of the key to generate")
+	cmd.Flags().StringP("encoding", "e", "hex", "Encoding of the key (hex, base64, base64url, base32, mnemonic)")
+	cmd.Flags().String("fingerprint", "", "Print the fingerprint of every key to stderr (sha256, jwk)")
+	cmd.Flags().Lookup("fingerprint").NoOptDefVal = string(fingerprint.SHA256)
+	addOutputFlags(cmd)
+
+	cmd.AddCommand(newKeyRestoreCommand(cfg), newKeyFingerprintCommand(cfg))
+
+	return cmd
+}
+
+// newKeyRestoreCommand creates the key restore subcommand.
+// It handles restoring a key from one of its encodings, such as a mnemonic backup.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newKeyRestoreCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [flags] [key|STDIN]",
+		Short: "Restore a key from its encoding",
+		Long: "Restore a key from one of its encodings and print it in another, by default from a mnemonic to hex.\n" +
+			"Mnemonic words are matched case-insensitively, may be abbreviated to their first four letters,\n" +
+			"and their checksum is verified.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			input, err := cobraext.PipeOrArg(args)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			cfg.Restore.Input = input
+
+			return cobraext.Validate(cfg, &cfg.Restore)
 		},
 		RunE: func(_ *cobra.Command, _ []string) error {
-			key, err := key.New(cfg.Generate.Length)
+			restored, err := key.Decode(cfg.Restore.Input, cfg.Restore.From)
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			encoded, err := restored.Encode(cfg.Restore.Encoding)
 			if err != nil {
-				return fmt.Errorf("generating key: %w", err)
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
 			}
 
-			fmt.Print(key.AsHex())
+			fmt.Print(encoded)
 
 			return nil
 		},
 	}
 
-	const length = 32
-
-	cmd.Flags().IntP("length", "l", length, "Length of the key to generate")
+	cmd.Flags().String("from", "mnemonic", "Encoding of the key to restore (hex, base64, base64url, base32, mnemonic)")
+	cmd.Flags().StringP("encoding", "e", "hex", "Encoding of the restored key (hex, base64, base64url, base32, mnemonic)")
 
 	return cmd
 }
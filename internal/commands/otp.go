@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/otp"
+	"github.com/idelchi/gogen/pkg/qr"
+)
+
+// NewOTPCommand creates the otp subcommand for one-time password operations.
+// It groups the commands for enrolling and using HOTP/TOTP secrets.
+func NewOTPCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "otp",
+		Short: "Work with one-time passwords",
+		Long:  "Generate HOTP/TOTP secrets for two-factor authentication, and compute or check their codes.",
+		RunE:  cobraext.UnknownSubcommandAction,
+	}
+
+	cmd.AddCommand(newOTPSecretCommand(cfg), newOTPCodeCommand(cfg), newOTPCheckCommand(cfg))
+
+	return cmd
+}
+
+// newOTPSecretCommand creates the otp secret subcommand.
+// It generates a secret and prints it along with its otpauth URI and optional QR code.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newOTPSecretCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Generate a one-time password secret",
+		Long: "Generate a base32 encoded one-time password secret and print it together with its " +
+			"otpauth:// URI, optionally rendered as a QR code in the terminal or as PNG image.",
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.OTP, &cfg.OTPSecret)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			secret, err := otp.NewSecret(cfg.OTPSecret.Size)
+			if err != nil {
+				return fmt.Errorf("generating secret: %w", err)
+			}
+
+			key := otp.Key{
+				Type:      otp.Type(cfg.OTP.Type),
+				Issuer:    cfg.OTPSecret.Issuer,
+				Account:   cfg.OTPSecret.Account,
+				Secret:    secret,
+				Algorithm: otp.Algorithm(cfg.OTP.Algorithm),
+				Digits:    cfg.OTP.Digits,
+				Period:    cfg.OTP.Period,
+				Counter:   cfg.OTP.Counter,
+			}
+
+			uri := key.URI()
+
+			fmt.Println(secret)
+			fmt.Println(uri)
+
+			if cfg.OTPSecret.QR {
+				code, err := qr.Terminal(uri)
+				if err != nil {
+					return fmt.Errorf("rendering qr code: %w", err)
+				}
+
+				fmt.Print(code)
+			}
+
+			if cfg.OTPSecret.PNG != "" {
+				if err := qr.PNG(uri, cfg.OTPSecret.PNGSize, cfg.OTPSecret.PNG); err != nil {
+					return fmt.Errorf("writing qr code: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	const (
+		size    = 20
+		pngSize = 256
+	)
+
+	cmd.Flags().IntP("size", "n", size, "Size of the secret in bytes")
+	cmd.Flags().StringP("issuer", "i", "", "Issuer of the key, e.g. the service name")
+	cmd.Flags().StringP("account", "a", "", "Account name the secret is enrolled for")
+	cmd.Flags().BoolP("qr", "q", false, "Render the key URI as QR code in the terminal")
+	cmd.Flags().String("png", "", "Write the QR code as PNG image to the given path")
+	cmd.Flags().Int("png-size", pngSize, "Width and height of the PNG image in pixels")
+	addOTPFlags(cmd)
+
+	return cmd
+}
+
+// newOTPCodeCommand creates the otp code subcommand.
+// It computes the code for a secret read from the argument or stdin.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newOTPCodeCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "code [flags] [secret|STDIN]",
+		Short: "Compute a one-time password code",
+		Long:  "Compute a HOTP (RFC 4226) or TOTP (RFC 6238) code for a base32 encoded secret.",
+		Args:  cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			return validateOTPCode(cfg, args)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			at, err := parseOTPTime(cfg.OTPCode.At)
+			if err != nil {
+				return err
+			}
+
+			code, err := otpKey(cfg).Code(at)
+			if err != nil {
+				return fmt.Errorf("computing code: %w", err)
+			}
+
+			fmt.Print(code)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("at", "", "Timestamp to compute the TOTP code for (RFC 3339 or Unix seconds)")
+	addOTPFlags(cmd)
+
+	return cmd
+}
+
+// newOTPCheckCommand creates the otp check subcommand.
+// It validates a code against a secret read from the argument or stdin.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newOTPCheckCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [flags] code [secret|STDIN]",
+		Short: "Check a one-time password code",
+		Long: "Check a HOTP or TOTP code against a base32 encoded secret, allowing for drift.\n" +
+			"TOTP codes are accepted within --window steps before and after the current time, " +
+			"HOTP codes within --window counters ahead of --counter.",
+		Args: cobra.RangeArgs(1, 2), //nolint:mnd	// Code and optional secret.
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			return validateOTPCode(cfg, args[1:])
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			at, err := parseOTPTime(cfg.OTPCode.At)
+			if err != nil {
+				return err
+			}
+
+			offset, err := otpKey(cfg).Check(args[0], at, cfg.OTPCode.Window)
+			if err != nil {
+				return fmt.Errorf("checking code: %w", err)
+			}
+
+			if offset != 0 {
+				fmt.Printf("valid (offset %+d)\n", offset)
+			} else {
+				fmt.Println("valid")
+			}
+
+			return nil
+		},
+	}
+
+	const window = 1
+
+	cmd.Flags().String("at", "", "Timestamp to check the TOTP code at (RFC 3339 or Unix seconds)")
+	cmd.Flags().IntP("window", "w", window, "Number of steps or counters a code may drift")
+	addOTPFlags(cmd)
+
+	return cmd
+}
+
+// validateOTPCode reads the secret from the arguments or stdin and validates the code configuration.
+func validateOTPCode(cfg *config.Config, args []string) error {
+	arg, err := cobraext.PipeOrArg(args)
+	if err != nil {
+		return fmt.Errorf("reading secret: %w", err)
+	}
+
+	cfg.OTPCode.Secret = arg
+
+	return cobraext.Validate(cfg, &cfg.OTP, &cfg.OTPCode)
+}
+
+// otpKey builds the one-time password key from the configuration.
+func otpKey(cfg *config.Config) otp.Key {
+	return otp.Key{
+		Type:      otp.Type(cfg.OTP.Type),
+		Secret:    cfg.OTPCode.Secret,
+		Algorithm: otp.Algorithm(cfg.OTP.Algorithm),
+		Digits:    cfg.OTP.Digits,
+		Period:    cfg.OTP.Period,
+		Counter:   cfg.OTP.Counter,
+	}
+}
+
+// parseOTPTime parses a timestamp given as RFC 3339 or Unix seconds.
+// An empty timestamp yields the current time.
+func parseOTPTime(timestamp string) (time.Time, error) {
+	if timestamp == "" {
+		return time.Now(), nil
+	}
+
+	if seconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	at, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid timestamp %q: expected RFC 3339 or Unix seconds", config.ErrUsage, timestamp)
+	}
+
+	return at, nil
+}
+
+// addOTPFlags registers the flags shared by all one-time password subcommands.
+func addOTPFlags(cmd *cobra.Command) {
+	const (
+		digits = 6
+		period = 30
+	)
+
+	cmd.Flags().StringP("type", "t", string(otp.TOTP), "Type of one-time password (totp, hotp)")
+	cmd.Flags().String("algorithm", string(otp.SHA1), "HMAC algorithm (SHA1, SHA256, SHA512)")
+	cmd.Flags().IntP("digits", "d", digits, "Number of digits of a code (6-8)")
+	cmd.Flags().IntP("period", "p", period, "Validity of a TOTP code in seconds")
+	cmd.Flags().Uint64("counter", 0, "HOTP counter")
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/output"
+)
+
+// addOutputFlags registers the flags for generating and printing multiple values.
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("count", "n", 1, "Number of values to generate")
+	cmd.Flags().StringSlice("names", nil, "Names to label the values with, one value is generated per name")
+	cmd.Flags().String("names-file", "", "File with one name per line to label the values with")
+	cmd.Flags().StringP("format", "f", string(output.Text), "Output format (text, json)")
+	cmd.Flags().BoolP("null", "0", false, "Separate text output with NUL characters instead of newlines")
+}
+
+// emit generates the configured number of values and prints them in the configured format.
+// A single unnamed value in text format is printed without a trailing separator.
+//
+//nolint:forbidigo	// Function prints out to the console.
+func emit(cmd *cobra.Command, cfg config.Output, generate func() (string, error)) error {
+	names, err := outputNames(cfg)
+	if err != nil {
+		return err
+	}
+
+	count := cfg.Count
+
+	if len(names) > 0 {
+		if cmd.Flags().Changed("count") && count != len(names) {
+			return fmt.Errorf("%w: --count %d does not match the number of names (%d)", config.ErrUsage, count, len(names))
+		}
+
+		count = len(names)
+	}
+
+	entries := make([]output.Entry, count)
+
+	for index := range entries {
+		value, err := generate()
+		if err != nil {
+			return err
+		}
+
+		entries[index].Value = value
+
+		if len(names) > 0 {
+			entries[index].Name = names[index]
+		}
+	}
+
+	format := output.Format(cfg.Format)
+
+	if format == output.Text && count == 1 && len(names) == 0 {
+		fmt.Print(entries[0].Value)
+
+		return nil
+	}
+
+	separator := "\n"
+	if cfg.Null {
+		separator = "\x00"
+	}
+
+	return output.Write(os.Stdout, entries, format, separator) //nolint:wrapcheck	// Error does not need additional wrapping.
+}
+
+// outputNames collects the names given as flag and in the names file.
+func outputNames(cfg config.Output) ([]string, error) {
+	names := cfg.Names
+
+	if cfg.NamesFile == "" {
+		return names, nil
+	}
+
+	file, err := os.Open(cfg.NamesFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening names file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading names file: %w", err)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/printer"
+	"github.com/idelchi/gogen/pkg/pw"
+)
+
+// NewPassphraseCommand creates the passphrase generation subcommand.
+// It handles generating diceware passphrases from embedded or user-supplied wordlists.
+func NewPassphraseCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "passphrase",
+		Short: "Generate a passphrase",
+		Long: "Generate a diceware passphrase from the embedded EFF wordlists or a wordlist file.\n" +
+			"Wordlist files contain one word per line, or lines in diceware format ('11111<TAB>word').",
+		Aliases: []string{"pp"},
+		Args:    cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.Passphrase, &cfg.Output)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			list, err := wordlist(cfg.Passphrase.Wordlist)
+			if err != nil {
+				return err
+			}
+
+			passphrase := pw.Passphrase{
+				Words:      cfg.Passphrase.Words,
+				Separator:  cfg.Passphrase.Separator,
+				Capitalize: cfg.Passphrase.Capitalize,
+				Digit:      cfg.Passphrase.Digit,
+				Symbol:     cfg.Passphrase.Symbol,
+			}
+
+			if err := emit(cmd, cfg.Output, func() (string, error) {
+				generated, err := passphrase.Generate(list)
+				if err != nil {
+					return "", fmt.Errorf("generating passphrase: %w", err)
+				}
+
+				return generated, nil
+			}); err != nil {
+				return err
+			}
+
+			if cfg.Passphrase.Entropy {
+				printer.Stderrln("\nentropy: %.1f bits (%d words from a list of %d)",
+					passphrase.Entropy(len(list)), passphrase.Words, len(list))
+			}
+
+			return nil
+		},
+	}
+
+	const words = 6
+
+	cmd.Flags().IntP("words", "w", words, "Number of words in the passphrase")
+	cmd.Flags().String("wordlist", "large", "Embedded wordlist (large, short) or path to a wordlist file")
+	cmd.Flags().StringP("separator", "S", "-", "Separator between the words")
+	cmd.Flags().BoolP("capitalize", "C", false, "Capitalize the first letter of every word")
+	cmd.Flags().BoolP("digit", "d", false, "Append a random digit to a random word")
+	cmd.Flags().Bool("symbol", false, "Append a random special character to a random word")
+	cmd.Flags().BoolP("entropy", "e", false, "Report the entropy of the passphrase on stderr")
+	addOutputFlags(cmd)
+
+	return cmd
+}
+
+// wordlist returns the embedded wordlist with the given name, or loads it from the given path.
+func wordlist(name string) (pw.Wordlist, error) {
+	if slices.Contains(pw.Wordlists(), name) {
+		return pw.EmbeddedWordlist(name) //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	list, err := pw.LoadWordlist(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading wordlist: %w", err)
+	}
+
+	return list, nil
+}
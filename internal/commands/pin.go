@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/pin"
+)
+
+// NewPinCommand creates the PIN generation subcommand.
+// It handles generating numeric codes, optionally rejecting weak patterns.
+func NewPinCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin",
+		Short: "Generate a PIN",
+		Long: "Generate numeric codes, such as door codes or SIM PINs.\n" +
+			"Weak patterns (repeated digits, sequences, dates, frequently chosen codes) can be rejected.\n" +
+			"Multiple codes generated in one invocation are unique.",
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.Pin, &cfg.Output)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			rules := pin.Rules{
+				NoRepeat:   cfg.Pin.NoRepeat || cfg.Pin.Strict,
+				NoSequence: cfg.Pin.NoSequence || cfg.Pin.Strict,
+				NoDates:    cfg.Pin.NoDates || cfg.Pin.Strict,
+				NoCommon:   cfg.Pin.NoCommon || cfg.Pin.Strict,
+			}
+
+			return emit(cmd, cfg.Output, unique(func() (string, error) {
+				code, err := pin.Generate(cfg.Pin.Length, rules)
+				if err != nil {
+					return "", fmt.Errorf("generating pin: %w", err)
+				}
+
+				return code, nil
+			}))
+		},
+	}
+
+	const length = 4
+
+	cmd.Flags().IntP("length", "l", length, "Number of digits")
+	cmd.Flags().Bool("no-repeat", false, "Reject codes with the same digit twice in a row (1123)")
+	cmd.Flags().Bool("no-sequence", false, "Reject codes with ascending or descending runs (1239, 9870)")
+	cmd.Flags().Bool("no-dates", false, "Reject codes that look like a year or a date (1987, 2412, 311299)")
+	cmd.Flags().Bool("no-common", false, "Reject frequently chosen codes (1234, 1111, 2580, ...)")
+	cmd.Flags().BoolP("strict", "s", false, "Reject all of the weak patterns above")
+	addOutputFlags(cmd)
+
+	return cmd
+}
+
+// unique wraps a generator to never return the same value twice.
+// It gives up when too many consecutive candidates were already returned, e.g. when the space is exhausted.
+func unique(generate generator) generator {
+	const maxAttempts = 1000
+
+	seen := make(map[string]struct{})
+
+	return func() (string, error) {
+		for range maxAttempts {
+			value, err := generate()
+			if err != nil {
+				return "", err
+			}
+
+			if _, ok := seen[value]; !ok {
+				seen[value] = struct{}{}
+
+				return value, nil
+			}
+		}
+
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("no unique value found after %d attempts, too few possible values", maxAttempts)
+	}
+}
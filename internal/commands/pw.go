@@ -1,44 +1,458 @@
 package commands
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
 	"github.com/idelchi/gogen/internal/config"
 	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/printer"
 	"github.com/idelchi/gogen/pkg/pw"
+	"github.com/idelchi/gogen/pkg/stdin"
 )
 
-// NewPasswordCommand creates the key generation subcommand.
-// It handles generating cryptographic keys of specified length.
-//
-//nolint:forbidigo	// Command prints out to the console.
+// NewPasswordCommand creates the password generation subcommand.
+// It handles generating passwords of specified length from configurable character classes.
 func NewPasswordCommand(cfg *config.Config) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "password",
-		Short:   "Generate a password",
-		Long:    "Generate a password of specified length",
+		Use:   "password",
+		Short: "Generate a password",
+		Long: "Generate a password of specified length.\n" +
+			"By default, passwords contain at least one lowercase letter, uppercase letter, digit and special character.\n\n" +
+			"With --derive, the password is derived from a prompted master secret, the site, login and counter,\n" +
+			"so the same inputs always yield the same password without storing anything.\n\n" +
+			"Patterns (--pattern) support the following syntax:\n\n" + pw.PatternSyntax,
 		Aliases: []string{"pw"},
 		Args:    cobra.NoArgs,
 		PreRunE: func(_ *cobra.Command, _ []string) error {
-			return cobraext.Validate(cfg, &cfg.Password)
+			if err := cobraext.Validate(cfg, &cfg.Password, &cfg.Output, &cfg.Strength); err != nil {
+				return err
+			}
+
+			return parseGuessRates(&cfg.Strength)
 		},
-		RunE: func(_ *cobra.Command, _ []string) error {
-			password, err := pw.Generate(cfg.Password.Length, true)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var (
+				source passwordSource
+				random io.Reader = rand.Reader
+				err    error
+			)
+
+			if cfg.Password.Derive {
+				if random, err = derivation(cmd, cfg.Password); err != nil {
+					return err
+				}
+			} else if err := requires(cmd, "derive", "site", "login", "counter"); err != nil {
+				return err
+			}
+
+			switch {
+			case cfg.Password.Pattern != "":
+				source, err = patternPassword(cmd, cfg.Password)
+			case cfg.Password.Pronounceable:
+				source, err = pronounceablePassword(cmd, cfg.Password)
+			case cfg.Password.Policy != "":
+				source, err = policyPassword(cmd, cfg, random)
+			default:
+				source, err = classPassword(cmd, cfg.Password, random)
+			}
+
 			if err != nil {
-				return fmt.Errorf("generating password: %w", err)
+				return err
 			}
 
-			fmt.Print(password)
+			if err := emit(cmd, cfg.Output, source.generate); err != nil {
+				return err
+			}
+
+			switch {
+			case cfg.Password.Stats:
+				rates, err := strengthRates(cfg.Strength)
+				if err != nil {
+					return err
+				}
+
+				printer.Stderrln("")
+				source.describe(os.Stderr)
+				printCrackTimes(os.Stderr, source.entropy, rates)
+			case cfg.Password.Entropy:
+				printer.Stderrln("\nentropy: %.1f bits", source.entropy)
+			}
 
 			return nil
 		},
 	}
 
-	const length = 16
+	const (
+		length    = 16
+		syllables = 5
+	)
 
 	cmd.Flags().IntP("length", "l", length, "Length of the password to generate")
+	cmd.Flags().Bool("no-lower", false, "Exclude lowercase letters")
+	cmd.Flags().Bool("no-upper", false, "Exclude uppercase letters")
+	cmd.Flags().Bool("no-digits", false, "Exclude digits")
+	cmd.Flags().Bool("no-special", false, "Exclude special characters")
+	cmd.Flags().String("symbols", pw.DefaultSpecial(), "Set of special characters to use")
+	cmd.Flags().String("alphabet", "", "Custom set of characters to use instead of the character classes")
+	cmd.Flags().String("exclude", "", "Characters that must not appear in the password")
+	cmd.Flags().BoolP("exclude-ambiguous", "A", false, "Exclude easily confused characters ("+pw.Ambiguous+")")
+	cmd.Flags().Int("min-lower", 1, "Minimum number of lowercase letters")
+	cmd.Flags().Int("min-upper", 1, "Minimum number of uppercase letters")
+	cmd.Flags().Int("min-digits", 1, "Minimum number of digits")
+	cmd.Flags().Int("min-special", 1, "Minimum number of special characters")
+	cmd.Flags().StringP("policy", "P", "", "Name of the password policy profile to satisfy")
+	cmd.Flags().String("policy-file", config.DefaultPolicyFile(), "Path to the file with password policy profiles")
+	cmd.Flags().String("pattern", "", "Pattern describing the shape of the password, e.g. 'Cvccvc-99-!!'")
+	cmd.Flags().BoolP("pronounceable", "p", false, "Generate a password made of pronounceable syllables")
+	cmd.Flags().Int("syllables", syllables, "Number of syllables of a pronounceable password")
+	cmd.Flags().Bool("derive", false, "Derive the password from a prompted master secret, the site, login and counter")
+	cmd.Flags().String("site", "", "Site or service to derive the password for")
+	cmd.Flags().String("login", "", "User name or email address to derive the password for")
+	cmd.Flags().Uint64("counter", 1, "Counter to rotate a derived password")
+	cmd.Flags().BoolP("entropy", "e", false, "Report the entropy of the password on stderr")
+	cmd.Flags().Bool("stats", false, "Report the alphabet, entropy and estimated crack times on stderr")
+	addStrengthFlags(cmd)
+	addOutputFlags(cmd)
+
+	cmd.AddCommand(newPasswordCheckCommand(cfg), newBreachIndexCommand(cfg))
 
 	return cmd
 }
+
+// generator generates a single value.
+type generator func() (string, error)
+
+// passwordSource generates passwords and describes their strength.
+type passwordSource struct {
+	// generate generates a single password
+	generate generator
+
+	// length is the length of the passwords, 0 if it varies
+	length int
+
+	// alphabet is the number of characters every position is drawn from, 0 if it varies by position
+	alphabet int
+
+	// entropy is the entropy of the passwords in bits
+	entropy float64
+}
+
+// describe writes the length, alphabet and entropy of the passwords.
+func (s passwordSource) describe(writer io.Writer) {
+	if s.length > 0 {
+		fmt.Fprintf(writer, "length:   %d characters\n", s.length)
+	} else {
+		fmt.Fprintf(writer, "length:   varies\n")
+	}
+
+	if s.alphabet > 0 {
+		fmt.Fprintf(writer, "alphabet: %d characters\n", s.alphabet)
+	} else {
+		fmt.Fprintf(writer, "alphabet: varies by position\n")
+	}
+
+	fmt.Fprintf(writer, "entropy:  %.1f bits\n", s.entropy)
+}
+
+// newPasswordSource returns the source of passwords of the given length drawn from the classes.
+func newPasswordSource(generate generator, length int, classes []pw.Class) passwordSource {
+	alphabet, _ := pw.Alphabet(classes)
+
+	return passwordSource{
+		generate: generate,
+		length:   length,
+		alphabet: len(alphabet),
+		entropy:  pw.Entropy(length, classes),
+	}
+}
+
+// derivation prompts for the master secret and returns the deterministic source of randomness
+// for the site, login and counter of the configuration.
+func derivation(cmd *cobra.Command, cfg config.Password) (io.Reader, error) {
+	// A derived password is only as strong as the master secret, so the entropy of the classes does not apply.
+	if err := exclusive(
+		cmd, "derive", "pattern", "pronounceable", "count", "names", "names-file", "entropy", "stats",
+	); err != nil {
+		return nil, err
+	}
+
+	if cfg.Site == "" {
+		return nil, fmt.Errorf("%w: --derive requires --site", config.ErrUsage)
+	}
+
+	master, err := stdin.Secret("Master secret: ")
+	if err != nil {
+		return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	derivation := pw.Derivation{Site: cfg.Site, Login: cfg.Login, Counter: cfg.Counter}
+
+	random, err := derivation.Random([]byte(master))
+	if err != nil {
+		return nil, fmt.Errorf("%w: deriving password: %w", config.ErrUsage, err)
+	}
+
+	return random, nil
+}
+
+// classPassword returns the source of passwords from the character classes of the configuration,
+// drawing the randomness from the given source.
+func classPassword(cmd *cobra.Command, cfg config.Password, random io.Reader) (passwordSource, error) {
+	classes, err := passwordClasses(cmd, cfg)
+	if err != nil {
+		return passwordSource{}, err
+	}
+
+	generate := func() (string, error) {
+		password, err := pw.GenerateWith(random, cfg.Length, classes)
+		if err != nil {
+			return "", fmt.Errorf("generating password: %w", err)
+		}
+
+		return password, nil
+	}
+
+	return newPasswordSource(generate, cfg.Length, classes), nil
+}
+
+// patternPassword returns the source of passwords matching the pattern of the configuration.
+func patternPassword(cmd *cobra.Command, cfg config.Password) (passwordSource, error) {
+	if err := exclusive(cmd, "pattern", append(classFlags, "length", "policy", "alphabet", "pronounceable", "exclude", "exclude-ambiguous")...); err != nil {
+		return passwordSource{}, err
+	}
+
+	pattern, err := pw.ParsePattern(cfg.Pattern)
+	if err != nil {
+		return passwordSource{}, fmt.Errorf("%w: parsing pattern: %w", config.ErrUsage, err)
+	}
+
+	generate := func() (string, error) {
+		password, err := pattern.Generate()
+		if err != nil {
+			return "", fmt.Errorf("generating password: %w", err)
+		}
+
+		return password, nil
+	}
+
+	return passwordSource{generate: generate, length: len(pattern), entropy: pattern.Entropy()}, nil
+}
+
+// pronounceablePassword returns the source of pronounceable passwords.
+// The minimum counts of the included classes determine the number of
+// capitalized syllables, digits and special characters.
+func pronounceablePassword(cmd *cobra.Command, cfg config.Password) (passwordSource, error) {
+	if err := exclusive(cmd, "pronounceable",
+		"length", "policy", "alphabet", "exclude", "exclude-ambiguous", "no-lower", "min-lower"); err != nil {
+		return passwordSource{}, err
+	}
+
+	included := func(excluded bool, minimum int) int {
+		if excluded {
+			return 0
+		}
+
+		return minimum
+	}
+
+	pronounceable := pw.Pronounceable{
+		Syllables: cfg.Syllables,
+		Upper:     included(cfg.NoUpper, cfg.MinUpper),
+		Digits:    included(cfg.NoDigits, cfg.MinDigits),
+		Special:   included(cfg.NoSpecial, cfg.MinSpecial),
+		Symbols:   cfg.Symbols,
+	}
+
+	generate := func() (string, error) {
+		password, err := pronounceable.Generate()
+		if err != nil {
+			return "", fmt.Errorf("generating password: %w", err)
+		}
+
+		return password, nil
+	}
+
+	return passwordSource{generate: generate, entropy: pronounceable.Entropy()}, nil
+}
+
+// policyPassword returns the source of passwords satisfying the selected policy profile,
+// drawing the randomness from the given source.
+// Unless the length is set explicitly, the longest length allowed by the policy is used.
+func policyPassword(cmd *cobra.Command, cfg *config.Config, random io.Reader) (passwordSource, error) {
+	if err := exclusive(cmd, "policy", append(classFlags, "alphabet")...); err != nil {
+		return passwordSource{}, err
+	}
+
+	profile, err := config.LoadPolicy(cfg.Password.PolicyFile, cfg.Password.Policy)
+	if err != nil {
+		return passwordSource{}, err
+	}
+
+	flags := cmd.Flags()
+
+	length := cfg.Password.Length
+
+	switch {
+	case flags.Changed("length"):
+		if length < profile.MinLength || (profile.MaxLength > 0 && length > profile.MaxLength) {
+			return passwordSource{}, fmt.Errorf("%w: length %d is outside the range allowed by policy %q",
+				config.ErrUsage, length, cfg.Password.Policy)
+		}
+	case profile.MaxLength > 0:
+		length = profile.MaxLength
+	default:
+		length = max(length, profile.MinLength)
+	}
+
+	classes, err := policyClasses(profile)
+	if err != nil {
+		return passwordSource{}, err
+	}
+
+	classes, err = exclusions(cfg.Password, classes)
+	if err != nil {
+		return passwordSource{}, err
+	}
+
+	policy := pw.Policy{
+		Classes:         classes,
+		Forbidden:       profile.Forbidden,
+		MaxRepeat:       profile.MaxRepeat,
+		StartWithLetter: profile.StartWithLetter,
+	}
+
+	generate := func() (string, error) {
+		password, err := policy.GenerateWith(random, length)
+		if err != nil {
+			return "", fmt.Errorf("generating password for policy %q: %w", cfg.Password.Policy, err)
+		}
+
+		return password, nil
+	}
+
+	return newPasswordSource(generate, length, classes), nil
+}
+
+// policyClasses builds the character classes allowed by a policy profile.
+func policyClasses(profile config.Policy) ([]pw.Class, error) {
+	all := []pw.Class{
+		pw.Lower(profile.MinLower),
+		pw.Upper(profile.MinUpper),
+		pw.Digits(profile.MinDigits),
+		pw.Special(profile.Symbols, profile.MinSpecial),
+	}
+
+	if len(profile.Classes) == 0 {
+		return all, nil
+	}
+
+	classes := make([]pw.Class, 0, len(all))
+
+	for _, class := range all {
+		if slices.Contains(profile.Classes, class.Name) {
+			classes = append(classes, class)
+		} else if class.Min > 0 {
+			return nil, fmt.Errorf("%w: policy requires %s characters which are not an allowed class",
+				config.ErrUsage, class.Name)
+		}
+	}
+
+	return classes, nil
+}
+
+// passwordClasses builds the character classes for password generation from the configuration.
+// Minimum counts of excluded classes are ignored, unless they were set explicitly.
+func passwordClasses(cmd *cobra.Command, cfg config.Password) ([]pw.Class, error) {
+	flags := cmd.Flags()
+
+	if cfg.Alphabet != "" {
+		if err := exclusive(cmd, "alphabet", classFlags...); err != nil {
+			return nil, err
+		}
+
+		return exclusions(cfg, []pw.Class{{Name: "alphabet", Chars: cfg.Alphabet}})
+	}
+
+	candidates := []struct {
+		excluded bool
+		minimum  string
+		class    pw.Class
+	}{
+		{cfg.NoLower, "min-lower", pw.Lower(cfg.MinLower)},
+		{cfg.NoUpper, "min-upper", pw.Upper(cfg.MinUpper)},
+		{cfg.NoDigits, "min-digits", pw.Digits(cfg.MinDigits)},
+		{cfg.NoSpecial, "min-special", pw.Special(cfg.Symbols, cfg.MinSpecial)},
+	}
+
+	classes := make([]pw.Class, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		if !candidate.excluded {
+			classes = append(classes, candidate.class)
+
+			continue
+		}
+
+		if flags.Changed(candidate.minimum) && candidate.class.Min > 0 {
+			return nil, fmt.Errorf("%w: --%s requires the %s class to be included",
+				config.ErrUsage, candidate.minimum, candidate.class.Name)
+		}
+	}
+
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("%w: at least one character class must be included", config.ErrUsage)
+	}
+
+	return exclusions(cfg, classes)
+}
+
+// classFlags are the flags configuring the character classes of a password.
+//
+//nolint:gochecknoglobals	// Constant list of flag names.
+var classFlags = []string{
+	"no-lower", "no-upper", "no-digits", "no-special", "symbols",
+	"min-lower", "min-upper", "min-digits", "min-special",
+}
+
+// exclusive returns a usage error if any of the other flags was set together with the given flag.
+func exclusive(cmd *cobra.Command, flag string, others ...string) error {
+	for _, name := range others {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("%w: --%s cannot be combined with --%s", config.ErrUsage, flag, name)
+		}
+	}
+
+	return nil
+}
+
+// requires returns a usage error if any of the other flags was set without the given flag.
+func requires(cmd *cobra.Command, flag string, others ...string) error {
+	for _, name := range others {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("%w: --%s requires --%s", config.ErrUsage, name, flag)
+		}
+	}
+
+	return nil
+}
+
+// exclusions removes the excluded and, if requested, ambiguous characters from the classes.
+func exclusions(cfg config.Password, classes []pw.Class) ([]pw.Class, error) {
+	excluded := cfg.Exclude
+	if cfg.ExcludeAmbiguous {
+		excluded += pw.Ambiguous
+	}
+
+	classes, err := pw.Exclude(classes, excluded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+	}
+
+	return classes, nil
+}
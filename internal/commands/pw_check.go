@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/breach"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/strength"
+)
+
+// newPasswordCheckCommand creates the password check subcommand.
+// It scores an arbitrary password with a zxcvbn estimator.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newPasswordCheckCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [flags] [password|STDIN]",
+		Short: "Check the strength of a password",
+		Long: "Score the strength of a password from 0 (too guessable) to 4 (very unguessable) with a zxcvbn estimator,\n" +
+			"detecting dictionary words, keyboard walks, dates, sequences, repetitions and l33t substitutions.\n\n" +
+			"With --breach-db, the password is additionally looked up in a local copy of the Pwned Passwords list\n" +
+			"(SHA-1 or NTLM, ordered by hash) or an index built from it with 'breach-index'.\n" +
+			"A password found in the breach database results in a non-zero exit code.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			arg, err := cobraext.PipeOrArg(args)
+			if err != nil {
+				return fmt.Errorf("reading password: %w", err)
+			}
+
+			cfg.PasswordCheck.Password = arg
+
+			if err := cobraext.Validate(cfg, &cfg.PasswordCheck, &cfg.Strength); err != nil {
+				return err
+			}
+
+			return parseGuessRates(&cfg.Strength)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			rates, err := strengthRates(cfg.Strength)
+			if err != nil {
+				return err
+			}
+
+			estimate := strength.Check(cfg.PasswordCheck.Password, cfg.PasswordCheck.UserInputs)
+
+			fmt.Printf("score:    %d/4 (%s)\n", estimate.Score, scoreNames[estimate.Score])
+			fmt.Printf("entropy:  %.1f bits\n", estimate.Entropy)
+
+			printCrackTimes(os.Stdout, estimate.Entropy, rates)
+
+			fmt.Println("matches:")
+
+			for _, match := range estimate.Matches {
+				fmt.Printf("  %-10s %-20q", match.Pattern, match.Token)
+
+				if match.Dictionary != "" {
+					fmt.Printf("  (%s)", match.Dictionary)
+				}
+
+				fmt.Println()
+			}
+
+			if cfg.PasswordCheck.BreachDB == "" {
+				return nil
+			}
+
+			return checkBreach(cfg.PasswordCheck.BreachDB, cfg.PasswordCheck.Password)
+		},
+	}
+
+	cmd.Flags().StringSlice("user-inputs", nil, "Words specific to the user, e.g. names, treated as dictionary words")
+	cmd.Flags().String("breach-db", "", "Path to a Pwned Passwords file (ordered by hash) or breach index to look the password up in")
+	addStrengthFlags(cmd)
+
+	return cmd
+}
+
+// checkBreach looks the password up in the breach database and prints the result.
+// It returns an error if the password was found.
+//
+//nolint:forbidigo	// Function prints out to the console.
+func checkBreach(path, password string) error {
+	db, err := breach.Open(path)
+	if err != nil {
+		return err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+	defer db.Close()
+
+	count, err := db.Lookup(password)
+	if err != nil {
+		return fmt.Errorf("looking up password: %w", err)
+	}
+
+	if count == 0 {
+		fmt.Printf("breached: no (%s)\n", db.Hash())
+
+		return nil
+	}
+
+	fmt.Printf("breached: yes, %d times (%s)\n", count, db.Hash())
+
+	return fmt.Errorf("%w: password appears %d times in the breach database", errBreached, count)
+}
+
+// errBreached indicates a password that appears in a breach database.
+var errBreached = errors.New("breached password")
+
+// newBreachIndexCommand creates the password breach-index subcommand.
+// It converts a Pwned Passwords text file into a binary index for fast lookups.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newBreachIndexCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "breach-index source destination",
+		Short: "Build a breach index from a Pwned Passwords file",
+		Long: "Convert a Pwned Passwords text file (SHA-1 or NTLM, ordered by hash) into a compact binary index,\n" +
+			"usable with 'check --breach-db' for faster lookups.",
+		Args: cobra.ExactArgs(2), //nolint:mnd	// Source and destination.
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			source, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("opening source: %w", err)
+			}
+			defer source.Close()
+
+			var records int64
+
+			// The index is written to a temporary file first, so a failed build leaves no partial index behind.
+			if err := writeOutput(args[1], func(destination io.Writer) error {
+				records, err = breach.BuildIndex(source, destination)
+				if err != nil {
+					return fmt.Errorf("building index: %w", err)
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			fmt.Printf("indexed %d hashes\n", records)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// scoreNames describes the zxcvbn scores.
+//
+//nolint:gochecknoglobals	// Constant lookup table.
+var scoreNames = [...]string{
+	"too guessable",
+	"very guessable",
+	"somewhat guessable",
+	"safely unguessable",
+	"very unguessable",
+}
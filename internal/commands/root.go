@@ -17,7 +17,7 @@ func NewRootCommand(cfg *config.Config, version string) *cobra.Command {
 	root.Long = "gogen is a tool for generating cryptographic keys, passwords and password hashes."
 
 	root.Flags().BoolP("show", "s", false, "Show the configuration and exit")
-	root.AddCommand(NewHashCommand(cfg), NewKeyCommand(cfg), NewPasswordCommand(cfg))
+	root.AddCommand(NewHashCommand(cfg), NewDigestCommand(cfg), NewHMACCommand(cfg), NewEncryptCommand(cfg), NewDecryptCommand(cfg), NewAgeCommand(cfg), NewWireGuardCommand(cfg), NewSplitCommand(cfg), NewCombineCommand(cfg), NewKeyCommand(cfg), NewConvertCommand(cfg), NewDeriveCommand(cfg), NewPasswordCommand(cfg), NewPassphraseCommand(cfg), NewSecretCommand(cfg), NewEnvCommand(cfg), NewPinCommand(cfg), NewIDCommand(cfg), NewOTPCommand(cfg))
 
 	return root
 }
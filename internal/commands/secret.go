@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/secret"
+)
+
+// NewSecretCommand creates the framework secret generation subcommand.
+// It handles generating secrets in the formats expected by web frameworks.
+func NewSecretCommand(cfg *config.Config) *cobra.Command {
+	var presets strings.Builder
+
+	for _, preset := range secret.Presets() {
+		fmt.Fprintf(&presets, "\n  %-9s %-30s %s", preset.Name, preset.Setting, preset.Format)
+	}
+
+	cmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Generate a secret for a web framework",
+		Long:  "Generate a secret in the exact format expected by a web framework.\n\nFrameworks:" + presets.String(),
+		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.Secret, &cfg.Output)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return emit(cmd, cfg.Output, func() (string, error) {
+				return secret.Generate(cfg.Secret.For) //nolint:wrapcheck	// Error does not need additional wrapping.
+			})
+		},
+	}
+
+	cmd.Flags().String("for", "", "Framework to generate the secret for (django, rails, laravel, fernet, flask, nextauth)")
+	addOutputFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/mnemonic"
+	"github.com/idelchi/gogen/pkg/shamir"
+	"github.com/idelchi/gogen/pkg/stdin"
+)
+
+// NewSplitCommand creates the secret splitting subcommand.
+// It handles splitting a secret into shares with Shamir's secret sharing.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func NewSplitCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "split [flags] [secret|STDIN]",
+		Short: "Split a secret into shares",
+		Long: "Split a secret into shares with Shamir's secret sharing over GF(256), one share per line.\n" +
+			"Any --threshold shares recover the secret with 'gogen combine', fewer reveal nothing about it.\n" +
+			"Every share carries an identifier of its split, the threshold, its index and a checksum, so bad shares are detected.\n" +
+			"Prefer passing the secret through stdin, e.g. 'gogen key | gogen split', to keep it out of the shell history.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			secret, err := cobraext.PipeOrArg(args)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			cfg.Split.Secret = secret
+
+			return cobraext.Validate(cfg, &cfg.Split)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			shares, err := shamir.Split([]byte(cfg.Split.Secret), cfg.Split.Shares, cfg.Split.Threshold)
+			if err != nil {
+				return fmt.Errorf("%w: %w", config.ErrUsage, err)
+			}
+
+			for _, share := range shares {
+				encoded, err := encodeShare(share, cfg.Split.Encoding)
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(encoded)
+			}
+
+			return nil
+		},
+	}
+
+	const (
+		shares    = 5
+		threshold = 3
+	)
+
+	cmd.Flags().Int("shares", shares, "Number of shares to create")
+	cmd.Flags().IntP("threshold", "t", threshold, "Number of shares needed to recover the secret")
+	cmd.Flags().StringP("encoding", "e", "hex", "Encoding of the shares (hex, base64, mnemonic)")
+
+	return cmd
+}
+
+// NewCombineCommand creates the secret recovery subcommand.
+// It handles recovering a secret from shares created by the split subcommand.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func NewCombineCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "combine [flags] [share...|STDIN]",
+		Short: "Recover a secret from shares",
+		Long: "Recover a secret split with 'gogen split' from at least threshold shares.\n" +
+			"Shares are given as arguments, or through stdin with one share per line.",
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			shares, err := shareArgs(args)
+			if err != nil {
+				return err
+			}
+
+			cfg.Combine.Shares = shares
+
+			return cobraext.Validate(cfg, &cfg.Combine)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			shares := make([]shamir.Share, len(cfg.Combine.Shares))
+
+			for i, encoded := range cfg.Combine.Shares {
+				share, err := decodeShare(encoded, cfg.Combine.Encoding)
+				if err != nil {
+					return fmt.Errorf("share %d: %w", i+1, err)
+				}
+
+				shares[i] = share
+			}
+
+			secret, err := shamir.Combine(shares)
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			fmt.Print(string(secret))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("encoding", "e", "hex", "Encoding of the shares (hex, base64, mnemonic)")
+
+	return cmd
+}
+
+// shareArgs returns the shares given as arguments, or the non-empty lines of stdin.
+func shareArgs(args []string) ([]string, error) {
+	if len(args) > 0 || !stdin.IsPiped() {
+		return args, nil
+	}
+
+	var shares []string
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			shares = append(shares, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading shares from stdin: %w", err)
+	}
+
+	return shares, nil
+}
+
+// encodeShare encodes the share in the given encoding.
+func encodeShare(share shamir.Share, encoding string) (string, error) {
+	switch encoding {
+	case "base64":
+		return base64.StdEncoding.EncodeToString(share.Bytes()), nil
+	case "mnemonic":
+		return mnemonic.Encode(share.Bytes()) //nolint:wrapcheck	// Error does not need additional wrapping.
+	default:
+		return hex.EncodeToString(share.Bytes()), nil
+	}
+}
+
+// decodeShare decodes a share in the given encoding and verifies its checksum.
+func decodeShare(encoded, encoding string) (shamir.Share, error) {
+	var (
+		decoded []byte
+		err     error
+	)
+
+	switch encoding {
+	case "base64":
+		decoded, err = base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	case "mnemonic":
+		decoded, err = mnemonic.Decode(encoded)
+	default:
+		decoded, err = hex.DecodeString(strings.TrimSpace(encoded))
+	}
+
+	if err != nil {
+		return shamir.Share{}, fmt.Errorf("decoding %s share: %w", encoding, err)
+	}
+
+	return shamir.Parse(decoded) //nolint:wrapcheck	// Error does not need additional wrapping.
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/hash"
+	"github.com/idelchi/gogen/pkg/strength"
+)
+
+// addStrengthFlags registers the flags for estimating crack times.
+func addStrengthFlags(cmd *cobra.Command) {
+	const cost = 12
+
+	cmd.Flags().IntP("cost", "c", cost, "bcrypt cost to estimate the offline guess rate of a GPU for (4-31)")
+	cmd.Flags().StringSlice("guess-rates", nil, "Custom guess rates per second replacing the default scenarios, e.g. 1e4,1e10")
+}
+
+// parseGuessRates parses the custom guess rates of the configuration.
+func parseGuessRates(cfg *config.Strength) error {
+	cfg.Rates = cfg.Rates[:0]
+
+	for _, value := range cfg.GuessRates {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return fmt.Errorf("%w: invalid guess rate %q: must be a positive number", config.ErrUsage, value)
+		}
+
+		cfg.Rates = append(cfg.Rates, rate)
+	}
+
+	return nil
+}
+
+// strengthRates returns the attack scenarios to estimate crack times for.
+// It consists of the default or parsed custom guess rates, and the bcrypt guess rate estimated for a GPU.
+func strengthRates(cfg config.Strength) ([]strength.Rate, error) {
+	rates := strength.DefaultRates()
+
+	if len(cfg.Rates) > 0 {
+		rates = rates[:0]
+
+		for _, rate := range cfg.Rates {
+			rates = append(rates, strength.Rate{Name: "custom", PerSecond: rate})
+		}
+	}
+
+	estimated, err := hash.Rate(cfg.Cost)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+	}
+
+	rates = append(rates, strength.Rate{
+		Name:      fmt.Sprintf("offline, bcrypt cost %d, 1 GPU", cfg.Cost),
+		PerSecond: estimated,
+	})
+
+	return rates, nil
+}
+
+// printCrackTimes writes the average time needed to crack a password with the given entropy for every rate.
+func printCrackTimes(writer io.Writer, entropy float64, rates []strength.Rate) {
+	fmt.Fprintln(writer, "crack time (average):")
+
+	for _, rate := range rates {
+		fmt.Fprintf(writer, "  %-34s %-14s %s\n",
+			rate.Name,
+			strconv.FormatFloat(rate.PerSecond, 'g', 3, 64)+"/s",
+			strength.Humanize(rate.CrackTime(entropy)))
+	}
+}
@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/idelchi/gogen/internal/config"
+	"github.com/idelchi/gogen/pkg/cobraext"
+	"github.com/idelchi/gogen/pkg/fingerprint"
+	"github.com/idelchi/gogen/pkg/output"
+	"github.com/idelchi/gogen/pkg/wg"
+)
+
+// NewWireGuardCommand creates the WireGuard key generation subcommand.
+// It handles generating key pairs and preshared keys, optionally as a configuration snippet.
+func NewWireGuardCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wg",
+		Short: "Generate WireGuard keys",
+		Long: "Generate a WireGuard private key, its public key and optionally a preshared key, in base64.\n" +
+			"With --config, a ready-to-edit [Interface]/[Peer] configuration snippet is printed instead.",
+		Aliases: []string{"wireguard"},
+		Args:    cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return cobraext.Validate(cfg, &cfg.WireGuard)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			keys, err := wireGuardKeys(cfg.WireGuard.PSK, cfg.WireGuard.Fingerprint)
+			if err != nil {
+				return err
+			}
+
+			if !cfg.WireGuard.Config {
+				if err := requires(cmd, "config", "address", "listen-port", "peer", "endpoint", "allowed-ips"); err != nil {
+					return err
+				}
+
+				//nolint:wrapcheck	// Error does not need additional wrapping.
+				return output.Write(os.Stdout, keys, output.Format(cfg.WireGuard.Format), "\n")
+			}
+
+			if cmd.Flags().Changed("format") {
+				return fmt.Errorf("%w: --format cannot be combined with --config", config.ErrUsage)
+			}
+
+			if cfg.WireGuard.Peer != "" {
+				if _, err := wg.ParseKey(cfg.WireGuard.Peer); err != nil {
+					return fmt.Errorf("%w: parsing peer public key: %w", config.ErrUsage, err)
+				}
+			}
+
+			writeWireGuardConfig(os.Stdout, cfg.WireGuard, keys)
+
+			return nil
+		},
+	}
+
+	const listenPort = 51820
+
+	cmd.Flags().Bool("psk", false, "Also generate a preshared key")
+	cmd.Flags().BoolP("config", "c", false, "Print a configuration snippet instead of the keys")
+	cmd.Flags().String("address", "", "Address of the interface in the configuration snippet, e.g. 10.0.0.2/32")
+	cmd.Flags().Int("listen-port", listenPort, "Listen port of the interface in the configuration snippet, 0 to omit it")
+	cmd.Flags().String("peer", "", "Public key of the peer in the configuration snippet")
+	cmd.Flags().String("endpoint", "", "Endpoint of the peer in the configuration snippet, e.g. vpn.example.com:51820")
+	cmd.Flags().StringSlice("allowed-ips", []string{"0.0.0.0/0", "::/0"}, "Allowed IPs of the peer in the configuration snippet")
+	cmd.Flags().StringP("format", "f", string(output.Text), "Output format of the keys (text, json)")
+	cmd.Flags().String("fingerprint", "", "Also print the fingerprint of the public key (sha256, jwk)")
+	cmd.Flags().Lookup("fingerprint").NoOptDefVal = string(fingerprint.SHA256)
+
+	cmd.AddCommand(newWireGuardPubkeyCommand(cfg))
+
+	return cmd
+}
+
+// newWireGuardPubkeyCommand creates the wg pubkey subcommand.
+// It derives the public key of a private key, like wg pubkey.
+//
+//nolint:forbidigo	// Command prints out to the console.
+func newWireGuardPubkeyCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pubkey [private-key|STDIN]",
+		Short: "Derive the public key of a WireGuard private key",
+		Long:  "Derive the base64 public key of a base64 WireGuard private key, like 'wg pubkey'.",
+		Args:  cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			arg, err := cobraext.PipeOrArg(args)
+			if err != nil {
+				return fmt.Errorf("reading private key: %w", err)
+			}
+
+			cfg.WireGuard.PrivateKey = arg
+
+			return cobraext.Validate(cfg)
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			private, err := wg.ParseKey(cfg.WireGuard.PrivateKey)
+			if err != nil {
+				return fmt.Errorf("%w: parsing private key: %w", config.ErrUsage, err)
+			}
+
+			public, err := private.Public()
+			if err != nil {
+				return err //nolint:wrapcheck	// Error does not need additional wrapping.
+			}
+
+			fmt.Println(public)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// wireGuardKeys generates a key pair and, if requested, a preshared key
+// and the fingerprint of the public key with the given algorithm.
+func wireGuardKeys(psk bool, algorithm string) ([]output.Entry, error) {
+	private, err := wg.NewPrivateKey()
+	if err != nil {
+		return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	public, err := private.Public()
+	if err != nil {
+		return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	keys := []output.Entry{
+		{Name: "private", Value: private.String()},
+		{Name: "public", Value: public.String()},
+	}
+
+	if psk {
+		preshared, err := wg.NewPresharedKey()
+		if err != nil {
+			return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		keys = append(keys, output.Entry{Name: "psk", Value: preshared.String()})
+	}
+
+	if algorithm != "" {
+		x25519, err := public.X25519()
+		if err != nil {
+			return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		identifier, err := fingerprint.Of(x25519, fingerprint.Algorithm(algorithm))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", config.ErrUsage, err)
+		}
+
+		keys = append(keys, output.Entry{Name: "fingerprint", Value: identifier})
+	}
+
+	return keys, nil
+}
+
+// entryValue returns the value of the entry with the given name, or an empty string.
+func entryValue(entries []output.Entry, name string) string {
+	for _, entry := range entries {
+		if entry.Name == name {
+			return entry.Value
+		}
+	}
+
+	return ""
+}
+
+// writeWireGuardConfig writes a configuration snippet with the generated keys.
+// Values that were not given are left as placeholders to edit.
+func writeWireGuardConfig(writer io.Writer, cfg config.WireGuard, keys []output.Entry) {
+	orPlaceholder := func(value, placeholder string) string {
+		if value == "" {
+			return placeholder
+		}
+
+		return value
+	}
+
+	fmt.Fprintf(writer, "[Interface]\n")
+	fmt.Fprintf(writer, "# PublicKey = %s\n", entryValue(keys, "public"))
+
+	if identifier := entryValue(keys, "fingerprint"); identifier != "" {
+		fmt.Fprintf(writer, "# Fingerprint = %s\n", identifier)
+	}
+
+	fmt.Fprintf(writer, "PrivateKey = %s\n", entryValue(keys, "private"))
+	fmt.Fprintf(writer, "Address = %s\n", orPlaceholder(cfg.Address, "<address>"))
+
+	if cfg.ListenPort > 0 {
+		fmt.Fprintf(writer, "ListenPort = %d\n", cfg.ListenPort)
+	}
+
+	fmt.Fprintf(writer, "\n[Peer]\n")
+	fmt.Fprintf(writer, "PublicKey = %s\n", orPlaceholder(cfg.Peer, "<peer public key>"))
+
+	if preshared := entryValue(keys, "psk"); preshared != "" {
+		fmt.Fprintf(writer, "PresharedKey = %s\n", preshared)
+	}
+
+	fmt.Fprintf(writer, "AllowedIPs = %s\n", strings.Join(cfg.AllowedIPs, ", "))
+	fmt.Fprintf(writer, "Endpoint = %s\n", orPlaceholder(cfg.Endpoint, "<peer endpoint>"))
+}
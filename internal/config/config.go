@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/idelchi/gogen/pkg/validator"
 )
@@ -14,12 +15,471 @@ var ErrUsage = errors.New("usage error")
 type Password struct {
 	// Length specifies the password length
 	Length int `validate:"min=1"`
+
+	// NoLower excludes lowercase letters
+	NoLower bool `mapstructure:"no-lower"`
+
+	// NoUpper excludes uppercase letters
+	NoUpper bool `mapstructure:"no-upper"`
+
+	// NoDigits excludes digits
+	NoDigits bool `mapstructure:"no-digits"`
+
+	// NoSpecial excludes special characters
+	NoSpecial bool `mapstructure:"no-special"`
+
+	// Symbols replaces the default set of special characters, and must not be empty unless they are excluded
+	Symbols string `validate:"required_unless=NoSpecial true,printascii"`
+
+	// Alphabet replaces all character classes with a custom set of characters
+	Alphabet string `validate:"printascii"`
+
+	// Exclude lists characters that must not appear in the password
+	Exclude string
+
+	// ExcludeAmbiguous removes easily confused characters (0O1lI)
+	ExcludeAmbiguous bool `mapstructure:"exclude-ambiguous"`
+
+	// MinLower specifies the minimum number of lowercase letters
+	MinLower int `mapstructure:"min-lower" validate:"min=0"`
+
+	// MinUpper specifies the minimum number of uppercase letters
+	MinUpper int `mapstructure:"min-upper" validate:"min=0"`
+
+	// MinDigits specifies the minimum number of digits
+	MinDigits int `mapstructure:"min-digits" validate:"min=0"`
+
+	// MinSpecial specifies the minimum number of special characters
+	MinSpecial int `mapstructure:"min-special" validate:"min=0"`
+
+	// Policy is the name of a password policy profile from the policy file
+	Policy string
+
+	// PolicyFile is the path to the file containing the password policy profiles
+	PolicyFile string `mapstructure:"policy-file"`
+
+	// Pattern describes the shape of the password, see pw.PatternSyntax
+	Pattern string
+
+	// Entropy enables reporting the entropy of the password on stderr
+	Entropy bool
+
+	// Stats enables reporting the alphabet, entropy and estimated crack times on stderr
+	Stats bool
+
+	// Pronounceable enables generating passwords made of pronounceable syllables
+	Pronounceable bool
+
+	// Syllables specifies the number of syllables of pronounceable passwords
+	Syllables int `validate:"min=1"`
+
+	// Derive enables deriving the password deterministically from a prompted master secret
+	Derive bool
+
+	// Site is the site or service a derived password is for
+	Site string
+
+	// Login is the user name or email address a derived password is for
+	Login string
+
+	// Counter is incremented to rotate a derived password
+	Counter uint64
+}
+
+// Pin holds parameters for numeric code generation.
+type Pin struct {
+	// Length specifies the number of digits
+	Length int `validate:"min=1,max=64"`
+
+	// NoRepeat rejects codes with the same digit twice in a row
+	NoRepeat bool `mapstructure:"no-repeat"`
+
+	// NoSequence rejects codes with ascending or descending runs of three digits
+	NoSequence bool `mapstructure:"no-sequence"`
+
+	// NoDates rejects codes that look like a year or a date
+	NoDates bool `mapstructure:"no-dates"`
+
+	// NoCommon rejects frequently chosen codes
+	NoCommon bool `mapstructure:"no-common"`
+
+	// Strict rejects all weak patterns
+	Strict bool
+}
+
+// ID holds parameters for identifier generation.
+type ID struct {
+	// Type specifies the kind of identifier
+	Type string `validate:"oneof=v4 v7 v5 v3 ulid ksuid nanoid snowflake"`
+
+	// Name is the name of name-based UUIDs (v5, v3)
+	Name string `mapstructure:"-"`
+
+	// Namespace is the namespace of name-based UUIDs, a predefined name or a UUID
+	Namespace string
+
+	// Upper formats UUIDs in uppercase
+	Upper bool
+
+	// NoHyphens formats UUIDs without hyphens
+	NoHyphens bool `mapstructure:"no-hyphens"`
+
+	// Alphabet specifies the characters of NanoIDs
+	Alphabet string `validate:"printascii"`
+
+	// Size specifies the length of NanoIDs
+	Size int `validate:"min=1"`
+
+	// Node specifies the node ID of Snowflake IDs
+	Node int `validate:"min=0,max=1023"`
+
+	// Epoch specifies the epoch of Snowflake IDs in Unix milliseconds
+	Epoch int64 `validate:"min=0"`
+}
+
+// Digest holds parameters for computing and checking file digests.
+type Digest struct {
+	// Files are the files to hash, or the checksum files to check
+	Files []string `mapstructure:"-"`
+
+	// Algorithm specifies the digest algorithm
+	Algorithm string `validate:"oneof=sha256 sha384 sha512 sha3-256 sha3-384 sha3-512 blake2b blake2s blake3"`
+
+	// Check enables verifying the digests listed in checksum files
+	Check bool
+
+	// Quiet disables printing OK for successfully verified files
+	Quiet bool
+
+	// IgnoreMissing skips missing files when checking
+	IgnoreMissing bool `mapstructure:"ignore-missing"`
+
+	// Jobs specifies the number of files hashed in parallel
+	Jobs int `validate:"min=1"`
+}
+
+// HMAC holds parameters for signing and verifying payloads with an HMAC.
+type HMAC struct {
+	// Input is the file to sign, or "-" for stdin
+	Input string `mapstructure:"-"`
+
+	// Algorithm specifies the hash function of the HMAC
+	Algorithm string `validate:"oneof=sha256 sha384 sha512"`
+
+	// Key is the encoded key
+	Key string `validate:"excluded_with=KeyFile"`
+
+	// KeyFile is the path to a file containing the encoded key
+	KeyFile string `mapstructure:"key-file"`
+
+	// KeyEncoding specifies the encoding of the key (hex, base64, raw)
+	KeyEncoding string `mapstructure:"key-encoding" validate:"oneof=hex base64 raw"`
+
+	// Encoding specifies the encoding of the output MAC (hex, base64)
+	Encoding string `validate:"oneof=hex base64"`
+
+	// Header specifies the signature header format (none, github, stripe)
+	Header string `validate:"oneof=none github stripe"`
+
+	// Verify is the MAC or signature header to verify instead of printing the MAC
+	Verify string
+
+	// Timestamp is the Unix time to sign Stripe-style payloads at, 0 for now
+	Timestamp int64 `validate:"min=0"`
+
+	// Tolerance is the maximum age of verified Stripe-style signatures, 0 to disable
+	Tolerance time.Duration `validate:"min=0"`
+}
+
+// Encrypt holds parameters for encrypting and decrypting files.
+type Encrypt struct {
+	// Input is the file to encrypt or decrypt, or "-" for stdin
+	Input string `mapstructure:"-"`
+
+	// Out is the file to write the result to, stdout if empty or "-"
+	Out string `mapstructure:"output"`
+
+	// Cipher specifies the AEAD used for encryption
+	Cipher string `validate:"omitempty,oneof=aes-256-gcm xchacha20-poly1305"`
+
+	// Key is the encoded 32-byte key
+	Key string `validate:"excluded_with=KeyFile"`
+
+	// KeyFile is the path to a file containing the encoded key
+	KeyFile string `mapstructure:"key-file"`
+
+	// KeyEncoding specifies the encoding of the key (hex, base64, raw)
+	KeyEncoding string `mapstructure:"key-encoding" validate:"oneof=hex base64 raw"`
+
+	// Passphrase enables deriving the key from a prompted passphrase
+	Passphrase bool
+
+	// PassphraseFile is the path to a file containing the passphrase
+	PassphraseFile string `mapstructure:"passphrase-file"`
+}
+
+// Env holds parameters for filling .env files with generated secrets.
+type Env struct {
+	// File is the .env file to fill
+	File string `mapstructure:"-" validate:"required"`
+
+	// Template is the .env template providing the keys and placeholders
+	Template string
+
+	// Out is the file to write the result to, the .env file if empty, stdout if "-"
+	Out string `mapstructure:"output"`
+
+	// Rotate enables regenerating existing values of keys marked in the template
+	Rotate bool
+
+	// Default is the placeholder spec used for keys with empty values
+	Default string `validate:"required"`
+}
+
+// Split holds parameters for splitting secrets into shares.
+type Split struct {
+	// Secret is the secret to split
+	Secret string `mapstructure:"-" validate:"required"`
+
+	// Shares is the number of shares to create
+	Shares int `validate:"min=2,max=255"`
+
+	// Threshold is the number of shares needed to recover the secret
+	Threshold int `validate:"min=2,ltefield=Shares"`
+
+	// Encoding specifies the encoding of the shares (hex, base64, mnemonic)
+	Encoding string `validate:"oneof=hex base64 mnemonic"`
+}
+
+// Combine holds parameters for recovering secrets from shares.
+type Combine struct {
+	// Shares are the encoded shares to combine
+	Shares []string `mapstructure:"-" validate:"required"`
+
+	// Encoding specifies the encoding of the shares (hex, base64, mnemonic)
+	Encoding string `validate:"oneof=hex base64 mnemonic"`
+}
+
+// Age holds parameters for age key generation and encryption.
+type Age struct {
+	// Input is the file to encrypt, decrypt or read identities from, or "-" for stdin
+	Input string `mapstructure:"-"`
+
+	// Out is the file to write the result to, stdout if empty or "-"
+	Out string `mapstructure:"output"`
+
+	// Recipients are the recipients ("age1...") to encrypt to
+	Recipients []string `mapstructure:"recipient"`
+
+	// RecipientsFiles are files with one recipient per line
+	RecipientsFiles []string `mapstructure:"recipients-file"`
+
+	// Identities are identity files to decrypt with
+	Identities []string `mapstructure:"identity"`
+
+	// Passphrase enables encrypting to a prompted passphrase
+	Passphrase bool
+
+	// PassphraseFile is the path to a file containing the passphrase
+	PassphraseFile string `mapstructure:"passphrase-file"`
+
+	// Armor enables ASCII-armored (PEM) output
+	Armor bool
+
+	// Fingerprint specifies the algorithm of the public key fingerprint printed to stderr, none if empty
+	Fingerprint string `validate:"omitempty,oneof=sha256 jwk"`
+}
+
+// WireGuard holds parameters for WireGuard key and configuration generation.
+type WireGuard struct {
+	// PrivateKey is the private key to derive the public key of
+	PrivateKey string `mapstructure:"-"`
+
+	// PSK enables generating a preshared key
+	PSK bool
+
+	// Config enables printing a configuration snippet instead of the keys
+	Config bool
+
+	// Address is the address of the interface in the configuration snippet
+	Address string
+
+	// ListenPort is the listen port of the interface in the configuration snippet, 0 to omit it
+	ListenPort int `mapstructure:"listen-port" validate:"min=0,max=65535"`
+
+	// Peer is the public key of the peer in the configuration snippet
+	Peer string
+
+	// Endpoint is the endpoint of the peer in the configuration snippet
+	Endpoint string
+
+	// AllowedIPs are the allowed IPs of the peer in the configuration snippet
+	AllowedIPs []string `mapstructure:"allowed-ips" validate:"dive,cidr"`
+
+	// Format specifies the output format of the keys (text, json)
+	Format string `validate:"oneof=text json"`
+
+	// Fingerprint specifies the algorithm of the public key fingerprint to print, none if empty
+	Fingerprint string `validate:"omitempty,oneof=sha256 jwk"`
+}
+
+// Strength holds parameters for estimating the time needed to crack passwords.
+type Strength struct {
+	// Cost is the bcrypt cost to estimate the guess rate of an offline attack with a GPU for
+	Cost int `validate:"min=4,max=31"`
+
+	// GuessRates replaces the default attack scenarios with custom guess rates per second
+	GuessRates []string `mapstructure:"guess-rates"`
+
+	// Rates are the parsed custom guess rates per second
+	Rates []float64 `mapstructure:"-"`
+}
+
+// PasswordCheck holds parameters for checking the strength of a password.
+type PasswordCheck struct {
+	// Password is the password to check
+	Password string `mapstructure:"-" validate:"required"`
+
+	// UserInputs are words specific to the user, e.g. names, treated as dictionary words
+	UserInputs []string `mapstructure:"user-inputs"`
+
+	// BreachDB is the path to a breach database (Pwned Passwords text file or gogen index)
+	BreachDB string `mapstructure:"breach-db"`
+}
+
+// Passphrase holds parameters for diceware passphrase generation.
+type Passphrase struct {
+	// Words specifies the number of words in the passphrase
+	Words int `validate:"min=1"`
+
+	// Wordlist is the name of an embedded wordlist (large, short) or the path to a wordlist file
+	Wordlist string `validate:"required"`
+
+	// Separator is placed between the words
+	Separator string
+
+	// Capitalize upper-cases the first letter of every word
+	Capitalize bool
+
+	// Digit appends a random digit to a random word
+	Digit bool
+
+	// Symbol appends a random special character to a random word
+	Symbol bool
+
+	// Entropy enables reporting the entropy of the passphrase on stderr
+	Entropy bool
 }
 
 // Generate holds parameters for key generation.
 type Generate struct {
 	// Length specifies the key length in bytes (32-512, must be multiple of 32)
 	Length int `validate:"min=32,max=512,multiple=32"`
+
+	// Encoding specifies the encoding of the key (hex, base64, base64url, base32, mnemonic)
+	Encoding string `validate:"oneof=hex base64 base64url base32 mnemonic"`
+
+	// Fingerprint specifies the algorithm of the fingerprints printed to stderr, none if empty
+	Fingerprint string `validate:"omitempty,oneof=sha256 jwk"`
+}
+
+// Fingerprint holds parameters for fingerprinting existing keys.
+type Fingerprint struct {
+	// Input is the file containing the key, or "-" for stdin
+	Input string `mapstructure:"-"`
+
+	// Algorithm specifies the fingerprint algorithm (sha256, ssh, jwk)
+	Algorithm string `validate:"oneof=sha256 ssh jwk"`
+
+	// From specifies the encoding of symmetric keys (hex, base64, base64url, base32, mnemonic)
+	From string `validate:"oneof=hex base64 base64url base32 mnemonic"`
+}
+
+// Convert holds parameters for converting keys between formats.
+type Convert struct {
+	// Input is the file containing the key, or "-" for stdin
+	Input string `mapstructure:"-"`
+
+	// Out is the file to write the converted key to, stdout if empty or "-"
+	Out string `mapstructure:"output"`
+
+	// To specifies the format to convert to (pkcs1, pkcs8, sec1, pkix, openssh, jwk)
+	To string `validate:"oneof=pkcs1 pkcs8 sec1 pkix openssh jwk"`
+
+	// Public enables writing the public key of a private key
+	Public bool
+
+	// PassphraseFile is the path to a file containing the passphrase of an encrypted input key
+	PassphraseFile string `mapstructure:"passphrase-file"`
+
+	// Encrypt enables encrypting the output key with a prompted passphrase
+	Encrypt bool
+
+	// NewPassphraseFile is the path to a file containing the passphrase to encrypt the output key with
+	NewPassphraseFile string `mapstructure:"new-passphrase-file"`
+
+	// Comment is the comment of OpenSSH keys
+	Comment string
+}
+
+// Restore holds parameters for restoring encoded keys.
+type Restore struct {
+	// Input is the encoded key
+	Input string `mapstructure:"-" validate:"required"`
+
+	// From specifies the encoding of the input (hex, base64, base64url, base32, mnemonic)
+	From string `validate:"oneof=hex base64 base64url base32 mnemonic"`
+
+	// Encoding specifies the encoding of the restored key (hex, base64, base64url, base32, mnemonic)
+	Encoding string `validate:"oneof=hex base64 base64url base32 mnemonic"`
+}
+
+// Secret holds parameters for framework secret generation.
+type Secret struct {
+	// For specifies the framework to generate the secret for
+	For string `validate:"oneof=django rails laravel fernet flask nextauth"`
+}
+
+// Derive holds parameters for key derivation.
+type Derive struct {
+	// Algorithm specifies the KDF (hkdf-sha256, hkdf-sha512, argon2id, scrypt)
+	Algorithm string `validate:"oneof=hkdf-sha256 hkdf-sha512 argon2id scrypt"`
+
+	// Key is the encoded master key for HKDF
+	Key string `validate:"excluded_with=KeyFile"`
+
+	// KeyFile is the path to a file containing the encoded master key
+	KeyFile string `mapstructure:"key-file"`
+
+	// KeyEncoding specifies the encoding of the master key (hex, base64, base64url, base32, mnemonic, raw)
+	KeyEncoding string `mapstructure:"key-encoding" validate:"oneof=hex base64 base64url base32 mnemonic raw"`
+
+	// PassphraseFile is the path to a file containing the passphrase for argon2id and scrypt
+	PassphraseFile string `mapstructure:"passphrase-file"`
+
+	// Info is the context the key is bound to, e.g. the name of a service
+	Info string
+
+	// Salt is the salt of the derivation
+	Salt string
+
+	// Length specifies the length of the derived key in bytes
+	Length int `validate:"min=1,max=1024"`
+
+	// Encoding specifies the encoding of the derived key (hex, base64, base64url, base32, mnemonic)
+	Encoding string `validate:"oneof=hex base64 base64url base32 mnemonic"`
+
+	// Iterations is the number of passes of argon2id
+	Iterations uint32 `validate:"min=1"`
+
+	// Memory is the memory of argon2id in KiB, at most 4 GiB
+	Memory uint32 `validate:"min=8,max=4194304"`
+
+	// Parallelism is the number of lanes of argon2id
+	Parallelism uint8 `validate:"min=1"`
+
+	// LogN is the base-2 logarithm of the CPU/memory cost of scrypt, at most 22 (4 GiB with r=8)
+	LogN int `mapstructure:"log-n" validate:"min=1,max=22"`
 }
 
 // Hash holds parameters for password hashing operations.
@@ -37,6 +497,75 @@ type Hash struct {
 	Type string `validate:"oneof=bcrypt argon2"`
 }
 
+// OTP holds the common parameters of one-time passwords.
+type OTP struct {
+	// Type specifies the kind of one-time password (totp, hotp)
+	Type string `validate:"oneof=totp hotp"`
+
+	// Algorithm specifies the HMAC hash function (SHA1, SHA256, SHA512)
+	Algorithm string `validate:"oneof=SHA1 SHA256 SHA512"`
+
+	// Digits specifies the number of digits of a code (6-8)
+	Digits int `validate:"min=6,max=8"`
+
+	// Period specifies the validity of a TOTP code in seconds
+	Period int `validate:"min=1"`
+
+	// Counter is the HOTP counter
+	Counter uint64
+}
+
+// OTPSecret holds parameters for one-time password secret generation.
+type OTPSecret struct {
+	// Size specifies the secret size in bytes
+	Size int `validate:"min=10,max=64"`
+
+	// Issuer is the provider or service the account belongs to
+	Issuer string
+
+	// Account is the name of the account the secret is enrolled for
+	Account string `validate:"required"`
+
+	// QR enables rendering the key URI as a QR code in the terminal
+	QR bool
+
+	// PNG is an optional path to write the QR code to as PNG image
+	PNG string
+
+	// PNGSize specifies the width and height of the PNG image in pixels
+	PNGSize int `mapstructure:"png-size" validate:"min=64,max=4096"`
+}
+
+// OTPCode holds parameters for one-time password code generation and validation.
+type OTPCode struct {
+	// Secret is the base32 encoded shared secret
+	Secret string `mapstructure:"-" validate:"required"`
+
+	// At is the timestamp to compute TOTP codes for (RFC 3339 or Unix seconds), defaults to now
+	At string
+
+	// Window is the number of steps or counters a code may drift
+	Window int `validate:"min=0,max=100"`
+}
+
+// Output holds parameters for generating and printing multiple values.
+type Output struct {
+	// Count specifies the number of values to generate
+	Count int `validate:"min=1"`
+
+	// Names labels the generated values, one value is generated per name
+	Names []string `validate:"dive,required"`
+
+	// NamesFile is the path to a file with one name per line
+	NamesFile string `mapstructure:"names-file"`
+
+	// Format specifies the output format (text, json)
+	Format string `validate:"oneof=text json"`
+
+	// Null separates text output with NUL characters instead of newlines
+	Null bool
+}
+
 // Config holds the application's configuration parameters.
 type Config struct {
 	// Show enables output display
@@ -45,11 +574,77 @@ type Config struct {
 	// Generate contains key generation settings
 	Generate Generate `mapstructure:",squash"`
 
+	// Fingerprint contains key fingerprint settings
+	Fingerprint Fingerprint `mapstructure:",squash"`
+
+	// Convert contains key format conversion settings
+	Convert Convert `mapstructure:",squash"`
+
+	// Restore contains key restoration settings
+	Restore Restore `mapstructure:",squash"`
+
+	// Secret contains framework secret settings
+	Secret Secret `mapstructure:",squash"`
+
+	// Derive contains key derivation settings
+	Derive Derive `mapstructure:",squash"`
+
 	// Hash contains password hashing settings
 	Hash Hash `mapstructure:",squash"`
 
 	// Password contains password generation settings
 	Password Password `mapstructure:",squash"`
+
+	// Passphrase contains passphrase generation settings
+	Passphrase Passphrase `mapstructure:",squash"`
+
+	// Pin contains numeric code generation settings
+	Pin Pin `mapstructure:",squash"`
+
+	// ID contains identifier generation settings
+	ID ID `mapstructure:",squash"`
+
+	// Digest contains file digest settings
+	Digest Digest `mapstructure:",squash"`
+
+	// HMAC contains HMAC signing and verification settings
+	HMAC HMAC `mapstructure:",squash"`
+
+	// Encrypt contains file encryption settings
+	Encrypt Encrypt `mapstructure:",squash"`
+
+	// Env contains .env file settings
+	Env Env `mapstructure:",squash"`
+
+	// Split contains secret splitting settings
+	Split Split `mapstructure:",squash"`
+
+	// Combine contains secret recovery settings
+	Combine Combine `mapstructure:",squash"`
+
+	// Age contains age key generation and encryption settings
+	Age Age `mapstructure:",squash"`
+
+	// WireGuard contains WireGuard key generation settings
+	WireGuard WireGuard `mapstructure:",squash"`
+
+	// Strength contains password strength estimation settings
+	Strength Strength `mapstructure:",squash"`
+
+	// PasswordCheck contains password check settings
+	PasswordCheck PasswordCheck `mapstructure:",squash"`
+
+	// Output contains settings for generating and printing multiple values
+	Output Output `mapstructure:",squash"`
+
+	// OTP contains common one-time password settings
+	OTP OTP `mapstructure:",squash"`
+
+	// OTPSecret contains one-time password secret generation settings
+	OTPSecret OTPSecret `mapstructure:",squash"`
+
+	// OTPCode contains one-time password code settings
+	OTPCode OTPCode `mapstructure:",squash"`
 }
 
 // Display returns the value of the Show field.
@@ -60,6 +655,12 @@ func (c Config) Display() bool {
 // Validate performs configuration validation using the validator package.
 // It returns a wrapped ErrUsage if any validation rules are violated.
 func (c Config) Validate(config any) error {
+	return validate(config)
+}
+
+// validate performs configuration validation with the custom validations registered.
+// It returns a wrapped ErrUsage if any validation rules are violated.
+func validate(config any) error {
 	validator := validator.New()
 
 	if err := registerMultiple(validator); err != nil {
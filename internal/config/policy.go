@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// Policy holds the rules of a named password policy profile, as loaded from a policy file.
+//
+// Example policy file (YAML):
+//
+//	policies:
+//	  oracle:
+//	    min-length: 12
+//	    max-length: 30
+//	    classes: [lower, upper, digits, special]
+//	    symbols: "#_$"
+//	    min-digits: 2
+//	    forbidden: [oracle, password]
+//	    max-repeat: 2
+//	    start-with-letter: true
+type Policy struct {
+	// MinLength specifies the minimum password length
+	MinLength int `mapstructure:"min-length" validate:"min=1"`
+
+	// MaxLength specifies the maximum password length, 0 for no limit
+	MaxLength int `mapstructure:"max-length" validate:"omitempty,gtefield=MinLength"`
+
+	// Classes lists the allowed character classes, all if empty
+	Classes []string `validate:"dive,oneof=lower upper digits special"`
+
+	// Symbols replaces the default set of special characters
+	Symbols string `validate:"printascii"`
+
+	// MinLower specifies the minimum number of lowercase letters
+	MinLower int `mapstructure:"min-lower" validate:"min=0"`
+
+	// MinUpper specifies the minimum number of uppercase letters
+	MinUpper int `mapstructure:"min-upper" validate:"min=0"`
+
+	// MinDigits specifies the minimum number of digits
+	MinDigits int `mapstructure:"min-digits" validate:"min=0"`
+
+	// MinSpecial specifies the minimum number of special characters
+	MinSpecial int `mapstructure:"min-special" validate:"min=0"`
+
+	// Forbidden lists sequences that must not appear in the password (case-insensitive)
+	Forbidden []string
+
+	// MaxRepeat specifies the maximum number of consecutive identical characters, 0 for no limit
+	MaxRepeat int `mapstructure:"max-repeat" validate:"min=0"`
+
+	// StartWithLetter requires the password to start with a letter
+	StartWithLetter bool `mapstructure:"start-with-letter"`
+}
+
+// DefaultPolicyFile returns the default location of the policy file,
+// `gogen/policies.yaml` in the user's configuration directory.
+func DefaultPolicyFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "policies.yaml"
+	}
+
+	return filepath.Join(dir, "gogen", "policies.yaml")
+}
+
+// LoadPolicy reads the named policy profile from the policy file.
+// The file format is derived from its extension (YAML, JSON or TOML).
+func LoadPolicy(path, name string) (Policy, error) {
+	policies := viper.New()
+
+	policies.SetConfigFile(path)
+
+	if err := policies.ReadInConfig(); err != nil {
+		return Policy{}, fmt.Errorf("reading policy file: %w", err)
+	}
+
+	key := "policies." + name
+
+	if !policies.IsSet(key) {
+		return Policy{}, fmt.Errorf("%w: policy %q not found in %q", ErrUsage, name, path)
+	}
+
+	var policy Policy
+
+	if err := policies.UnmarshalKey(key, &policy, func(c *mapstructure.DecoderConfig) {
+		c.ErrorUnused = true
+	}); err != nil {
+		return Policy{}, fmt.Errorf("%w: policy %q: %w", ErrUsage, name, err)
+	}
+
+	if err := validate(&policy); err != nil {
+		return Policy{}, fmt.Errorf("policy %q: %w", name, err)
+	}
+
+	return policy, nil
+}
@@ -11,6 +11,9 @@
 //	# Generate a password
 //	gogen password
 //
+//	# Generate a diceware passphrase
+//	gogen passphrase
+//
 //	# Hash a password with default cost (12)
 //	gogen hash password
 //
@@ -19,6 +22,9 @@
 //
 //	# Run password hashing benchmark
 //	gogen hash -b password
+//
+//	# Generate a TOTP secret and render its QR code
+//	gogen otp secret -i ACME -a ci@acme.com -q
 package main
 
 import (
@@ -0,0 +1,214 @@
+// Package agecrypt provides age (https://age-encryption.org) X25519 key generation and
+// file encryption to recipients or passphrases, compatible with the age and age-keygen tools.
+//
+// Example usage:
+//
+//	identity, err := agecrypt.Generate()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fmt.Print(identity.File(time.Now()))
+package agecrypt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// Identity is an age X25519 key pair.
+type Identity struct {
+	// Secret is the bech32-encoded secret key ("AGE-SECRET-KEY-1...")
+	Secret string
+
+	// Recipient is the bech32-encoded public key ("age1...")
+	Recipient string
+}
+
+// Generate creates a new X25519 identity.
+func Generate() (Identity, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return Identity{}, fmt.Errorf("generating identity: %w", err)
+	}
+
+	return Identity{Secret: identity.String(), Recipient: identity.Recipient().String()}, nil
+}
+
+// File formats the identity as an identity file, in the format written by age-keygen.
+func (i Identity) File(created time.Time) string {
+	return fmt.Sprintf("# created: %s\n# public key: %s\n%s\n", created.Format(time.RFC3339), i.Recipient, i.Secret)
+}
+
+// Recipients returns the recipients of the X25519 identities in an identity file.
+func Recipients(identities io.Reader) ([]string, error) {
+	parsed, err := age.ParseIdentities(identities)
+	if err != nil {
+		return nil, fmt.Errorf("parsing identities: %w", err)
+	}
+
+	recipients := make([]string, 0, len(parsed))
+
+	for _, identity := range parsed {
+		x25519, ok := identity.(*age.X25519Identity)
+		if !ok {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return nil, fmt.Errorf("unsupported identity type %T", identity)
+		}
+
+		recipients = append(recipients, x25519.Recipient().String())
+	}
+
+	return recipients, nil
+}
+
+// ParseRecipients parses recipients ("age1...") given one per element, or in recipient files,
+// which contain one recipient per line and may contain comments ("#") and empty lines.
+func ParseRecipients(recipients []string, files ...io.Reader) ([]age.Recipient, error) {
+	sources := append([]io.Reader{strings.NewReader(strings.Join(recipients, "\n"))}, files...)
+
+	var parsed []age.Recipient
+
+	for _, source := range sources {
+		content, err := io.ReadAll(source)
+		if err != nil {
+			return nil, fmt.Errorf("reading recipients: %w", err)
+		}
+
+		if len(bytes.TrimSpace(content)) == 0 {
+			continue
+		}
+
+		list, err := age.ParseRecipients(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("parsing recipients: %w", err)
+		}
+
+		parsed = append(parsed, list...)
+	}
+
+	if len(parsed) == 0 {
+		return nil, errors.New("no recipients") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	return parsed, nil
+}
+
+// Passphrase returns the scrypt recipient of a passphrase.
+func Passphrase(passphrase string) (age.Recipient, error) {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("creating passphrase recipient: %w", err)
+	}
+
+	return recipient, nil
+}
+
+// Encrypt encrypts everything read from the source to the recipients, optionally ASCII-armored.
+func Encrypt(destination io.Writer, source io.Reader, armored bool, recipients ...age.Recipient) error {
+	output := destination
+
+	var armorWriter io.WriteCloser
+
+	if armored {
+		armorWriter = armor.NewWriter(destination)
+		output = armorWriter
+	}
+
+	writer, err := age.Encrypt(output, recipients...)
+	if err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+
+	if _, err := io.Copy(writer, source); err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+
+	if armorWriter != nil {
+		if err := armorWriter.Close(); err != nil {
+			return fmt.Errorf("armoring: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Decryptor decrypts age files, detecting ASCII armor and whether they are encrypted to a passphrase.
+type Decryptor struct {
+	source *bufio.Reader
+}
+
+// NewDecryptor returns the decryptor of the age file read from the source.
+func NewDecryptor(source io.Reader) *Decryptor {
+	reader := bufio.NewReader(source)
+
+	var decryptor Decryptor
+
+	if peeked, _ := reader.Peek(len(armor.Header)); string(peeked) == armor.Header {
+		decryptor.source = bufio.NewReader(armor.NewReader(reader))
+	} else {
+		decryptor.source = reader
+	}
+
+	return &decryptor
+}
+
+// NeedsPassphrase reports whether the file is encrypted to a passphrase (scrypt) rather than to recipients.
+func (d *Decryptor) NeedsPassphrase() bool {
+	// The header starts with the version line, followed by the first recipient stanza.
+	const peek = 64
+
+	header, _ := d.source.Peek(peek)
+
+	_, stanza, _ := bytes.Cut(header, []byte("\n"))
+
+	return bytes.HasPrefix(stanza, []byte("-> scrypt "))
+}
+
+// Decrypt returns the reader of the plaintext, decrypted with the identities in the identity files.
+func (d *Decryptor) Decrypt(identityFiles ...io.Reader) (io.Reader, error) {
+	var identities []age.Identity
+
+	for _, file := range identityFiles {
+		parsed, err := age.ParseIdentities(file)
+		if err != nil {
+			return nil, fmt.Errorf("parsing identities: %w", err)
+		}
+
+		identities = append(identities, parsed...)
+	}
+
+	return d.decrypt(identities...)
+}
+
+// DecryptPassphrase returns the reader of the plaintext, decrypted with the passphrase.
+func (d *Decryptor) DecryptPassphrase(passphrase string) (io.Reader, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("creating passphrase identity: %w", err)
+	}
+
+	return d.decrypt(identity)
+}
+
+// decrypt returns the reader of the plaintext, decrypted with the identities.
+func (d *Decryptor) decrypt(identities ...age.Identity) (io.Reader, error) {
+	reader, err := age.Decrypt(d.source, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting: %w", err)
+	}
+
+	return reader, nil
+}
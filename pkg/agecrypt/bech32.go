@@ -0,0 +1,141 @@
+package agecrypt
+
+import (
+	"crypto/ecdh"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// charset is the alphabet of bech32 data characters.
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// PublicKey returns the X25519 public key of the identity, decoded from its recipient.
+func (i Identity) PublicKey() (*ecdh.PublicKey, error) {
+	hrp, data, err := decodeBech32(i.Recipient)
+	if err != nil {
+		return nil, fmt.Errorf("decoding recipient: %w", err)
+	}
+
+	if hrp != "age" {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("decoding recipient: unexpected prefix %q", hrp)
+	}
+
+	public, err := ecdh.X25519().NewPublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding recipient: %w", err)
+	}
+
+	return public, nil
+}
+
+// decodeBech32 decodes a bech32 string (BIP 173) into its human-readable part and data,
+// verifying its checksum. Unlike BIP 173, the length is not limited, as age secret keys exceed it.
+func decodeBech32(encoded string) (string, []byte, error) {
+	const checksumLength = 6
+
+	for i := range len(encoded) {
+		if encoded[i] < '!' || encoded[i] > '~' {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return "", nil, fmt.Errorf("invalid character %q", encoded[i])
+		}
+	}
+
+	if strings.ToLower(encoded) != encoded && strings.ToUpper(encoded) != encoded {
+		return "", nil, errors.New("mixed case") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	encoded = strings.ToLower(encoded)
+
+	separator := strings.LastIndexByte(encoded, '1')
+	if separator < 1 || separator+checksumLength+1 > len(encoded) {
+		return "", nil, errors.New("invalid separator position") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	hrp := encoded[:separator]
+	values := make([]byte, 0, len(encoded)-separator-1)
+
+	for _, char := range encoded[separator+1:] {
+		value := strings.IndexRune(charset, char)
+		if value < 0 {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return "", nil, fmt.Errorf("invalid character %q", char)
+		}
+
+		values = append(values, byte(value))
+	}
+
+	if polymod(append(expandHRP(hrp), values...)) != 1 {
+		return "", nil, errors.New("invalid checksum") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	data, err := regroup(values[:len(values)-checksumLength])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
+
+// polymod computes the bech32 checksum of the values.
+func polymod(values []byte) uint32 {
+	generator := [...]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	checksum := uint32(1)
+
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+
+		for i, g := range generator {
+			if (top>>i)&1 == 1 {
+				checksum ^= g
+			}
+		}
+	}
+
+	return checksum
+}
+
+// expandHRP expands the human-readable part for the checksum computation.
+func expandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, 2*len(hrp)+1)
+
+	for i := range len(hrp) {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+
+	expanded = append(expanded, 0)
+
+	for i := range len(hrp) {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+// regroup converts 5-bit groups to bytes, rejecting non-zero padding.
+func regroup(values []byte) ([]byte, error) {
+	var (
+		accumulator uint32
+		bits        uint
+		data        = make([]byte, 0, len(values)*5/8)
+	)
+
+	for _, value := range values {
+		accumulator = accumulator<<5 | uint32(value)
+		bits += 5
+
+		if bits >= 8 {
+			bits -= 8
+			data = append(data, byte(accumulator>>bits))
+		}
+	}
+
+	if bits >= 5 || accumulator&(1<<bits-1) != 0 {
+		return nil, errors.New("invalid padding") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	return data, nil
+}
@@ -0,0 +1,138 @@
+package agecrypt
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+// TestDecodeBech32Valid checks the valid bech32 test vectors of BIP 173.
+func TestDecodeBech32Valid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"A12UEL5L": "a",
+		"a12uel5l": "a",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs": "an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw":                                              "abcdef",
+		"11" + strings.Repeat("q", 82) + "c8247j":                                                    "1",
+		"?1ezyfcl": "?",
+	}
+
+	for encoded, want := range tests {
+		hrp, _, err := decodeBech32(encoded)
+		if err != nil {
+			t.Errorf("decodeBech32(%q) = %v", encoded, err)
+
+			continue
+		}
+
+		if hrp != want {
+			t.Errorf("decodeBech32(%q): hrp = %q, want %q", encoded, hrp, want)
+		}
+	}
+
+	// The data of the vector with the full alphabet, regrouped into bytes.
+	_, data, err := decodeBech32("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		0x00, 0x44, 0x32, 0x14, 0xc7, 0x42, 0x54, 0xb6, 0x35, 0xcf,
+		0x84, 0x65, 0x3a, 0x56, 0xd7, 0xc6, 0x75, 0xbe, 0x77, 0xdf,
+	}
+
+	if !bytes.Equal(data, want) {
+		t.Errorf("data = %x, want %x", data, want)
+	}
+}
+
+// TestDecodeBech32Invalid checks the invalid bech32 test vectors of BIP 173.
+// The vector exceeding the overall length limit is omitted, as the length is deliberately not limited.
+func TestDecodeBech32Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"\x201nwldj5":    "HRP character out of range",
+		"\x7f1axkwrx":    "HRP character out of range",
+		"\x801eym55h":    "HRP character out of range",
+		"pzry9x0s0muk":   "no separator character",
+		"1pzry9x0s0muk":  "empty HRP",
+		"x1b4n0q5v":      "invalid data character",
+		"li1dgmt3":       "too short checksum",
+		"de1lg7wt\xff":   "invalid character in checksum",
+		"A1G7SGD8":       "checksum calculated with uppercase form of HRP",
+		"10a06t8":        "empty HRP",
+		"1qzzfhee":       "empty HRP",
+		"a12UEL5L":       "mixed case",
+		"abcdef1qpzry9x": "invalid checksum",
+	}
+
+	for encoded, reason := range tests {
+		if _, _, err := decodeBech32(encoded); err == nil {
+			t.Errorf("decodeBech32(%q) succeeded: %s", encoded, reason)
+		}
+	}
+}
+
+// TestPublicKey checks that the public key of generated identities is the one age derives from the secret key.
+func TestPublicKey(t *testing.T) {
+	t.Parallel()
+
+	for range 10 {
+		identity, err := Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		public, err := identity.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := age.ParseX25519Identity(identity.Secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		recipient, err := age.ParseX25519Recipient(identity.Recipient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if recipient.String() != parsed.Recipient().String() {
+			t.Fatalf("recipient %s does not belong to the secret key", recipient)
+		}
+
+		// Independently of age, the public key must be the X25519 public key of the decoded secret key.
+		hrp, scalar, err := decodeBech32(identity.Secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hrp != "age-secret-key-" {
+			t.Errorf("secret key prefix = %q", hrp)
+		}
+
+		private, err := ecdh.X25519().NewPrivateKey(scalar)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !public.Equal(private.PublicKey()) {
+			t.Errorf("PublicKey() = %x, want %x", public.Bytes(), private.PublicKey().Bytes())
+		}
+	}
+
+	if _, err := (Identity{Recipient: "age1" + strings.Repeat("q", 58)}).PublicKey(); err == nil {
+		t.Error("invalid recipient succeeded")
+	}
+
+	if _, err := (Identity{Recipient: "a12uel5l"}).PublicKey(); err == nil {
+		t.Error("recipient with another prefix succeeded")
+	}
+}
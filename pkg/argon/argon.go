@@ -1,10 +1,11 @@
-// Package argon provides functionality for secure password hashing using Argon2id.
+// Package argon provides functionality for secure password hashing and key derivation using Argon2id.
 package argon
 
 import (
 	"fmt"
 
 	"github.com/alexedwards/argon2id"
+	"golang.org/x/crypto/argon2"
 )
 
 // Password generates an Argon2id hash of the provided password using default parameters.
@@ -22,3 +23,26 @@ func Password(password string) (string, error) {
 
 	return hash, nil
 }
+
+// Params are the cost parameters of Argon2id key derivation.
+type Params struct {
+	// Iterations is the number of passes over the memory
+	Iterations uint32
+
+	// Memory is the amount of memory used in KiB
+	Memory uint32
+
+	// Parallelism is the number of lanes
+	Parallelism uint8
+}
+
+// DefaultParams are the parameters used by Password.
+//
+//nolint:gochecknoglobals,mnd	// Default parameters, matching Password.
+var DefaultParams = Params{Iterations: 3, Memory: 64 * 1024, Parallelism: 4}
+
+// Key derives a key of the given length from the secret and salt using Argon2id.
+// The same secret, salt and parameters always yield the same key.
+func Key(secret, salt []byte, length uint32, params Params) []byte {
+	return argon2.IDKey(secret, salt, params.Iterations, params.Memory, params.Parallelism, length)
+}
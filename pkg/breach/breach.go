@@ -0,0 +1,273 @@
+// Package breach provides offline lookups of passwords in breach corpora,
+// such as the Pwned Passwords lists of Have I Been Pwned (HIBP).
+//
+// Two database formats are supported, both searched with a binary search without loading them into memory:
+//   - The text files published by HIBP, ordered by hash, with lines of the form "HASH:COUNT"
+//   - A compact binary index built from such a text file, with fixed-size records
+//
+// Databases contain either SHA-1 or NTLM hashes, which is detected when opening them.
+//
+// Example usage:
+//
+//	db, err := breach.Open("pwned-passwords-sha1-ordered-by-hash-v8.txt")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer db.Close()
+//
+//	count, err := db.Lookup("password")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" //nolint:gosec // SHA-1 is the hash used by the Pwned Passwords lists.
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4" //nolint:staticcheck // MD4 is required for NTLM hashes.
+)
+
+// Hash is the hash function of the passwords in a database.
+type Hash byte
+
+const (
+	// SHA1 databases contain uppercase hex SHA-1 hashes of the passwords.
+	SHA1 Hash = iota + 1
+
+	// NTLM databases contain uppercase hex MD4 hashes of the UTF-16LE encoded passwords.
+	NTLM
+)
+
+// String returns the name of the hash function.
+func (h Hash) String() string {
+	switch h {
+	case SHA1:
+		return "SHA-1"
+	case NTLM:
+		return "NTLM"
+	default:
+		return "unknown"
+	}
+}
+
+// Size returns the size of the hash in bytes.
+func (h Hash) Size() int {
+	switch h {
+	case SHA1:
+		return sha1.Size
+	case NTLM:
+		return md4.Size
+	default:
+		return 0
+	}
+}
+
+// Sum returns the hash of the password.
+func (h Hash) Sum(password string) []byte {
+	switch h {
+	case SHA1:
+		sum := sha1.Sum([]byte(password)) //nolint:gosec // SHA-1 is the hash used by the Pwned Passwords lists.
+
+		return sum[:]
+	case NTLM:
+		encoded := utf16.Encode([]rune(password))
+
+		hasher := md4.New()
+		_ = binary.Write(hasher, binary.LittleEndian, encoded)
+
+		return hasher.Sum(nil)
+	default:
+		return nil
+	}
+}
+
+// hashFromHexLength returns the hash function of a hex encoded hash of the given length.
+func hashFromHexLength(length int) (Hash, error) {
+	switch length {
+	case hex.EncodedLen(sha1.Size):
+		return SHA1, nil
+	case hex.EncodedLen(md4.Size):
+		return NTLM, nil
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return 0, fmt.Errorf("unsupported hash length %d: expected SHA-1 or NTLM hashes", length)
+	}
+}
+
+// ErrFormat indicates a database file with unexpected contents.
+var ErrFormat = errors.New("invalid breach database")
+
+// DB is an opened breach database.
+type DB struct {
+	file   *os.File
+	size   int64
+	hash   Hash
+	search func(digest []byte) (uint32, error)
+}
+
+// Open opens a breach database, detecting whether it is a binary index or a text file.
+func Open(path string) (*DB, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening breach database: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("opening breach database: %w", err)
+	}
+
+	db := &DB{file: file, size: info.Size()}
+
+	header := make([]byte, headerSize)
+
+	if _, err = file.ReadAt(header, 0); err == nil && bytes.HasPrefix(header, []byte(magic)) {
+		err = db.openIndex(header)
+	} else {
+		err = db.openText()
+	}
+
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Hash returns the hash function of the passwords in the database.
+func (db *DB) Hash() Hash {
+	return db.hash
+}
+
+// Lookup returns how often the password appears in the database, 0 if it does not appear.
+func (db *DB) Lookup(password string) (uint32, error) {
+	return db.search(db.hash.Sum(password))
+}
+
+// Close closes the database file.
+func (db *DB) Close() error {
+	return db.file.Close() //nolint:wrapcheck	// Error does not need additional wrapping.
+}
+
+// openText prepares searching a text database, detecting the hash function from its first line.
+func (db *DB) openText() error {
+	line, err := bufio.NewReader(io.NewSectionReader(db.file, 0, db.size)).ReadSlice('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading breach database: %w", err)
+	}
+
+	digest, _, err := parseLine(line)
+	if err != nil {
+		return err
+	}
+
+	db.hash, err = hashFromHexLength(len(digest))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	db.search = db.searchText
+
+	return nil
+}
+
+// searchText performs a binary search over the lines of a text database.
+// The invariant is that lower is the start of a line, and the line of the digest, if present, starts before upper.
+func (db *DB) searchText(digest []byte) (uint32, error) {
+	target := bytes.ToUpper([]byte(hex.EncodeToString(digest)))
+
+	var lower, upper int64 = 0, db.size
+
+	for lower < upper {
+		middle := lower + (upper-lower)/2
+
+		start, err := db.lineStart(lower, middle)
+		if err != nil {
+			return 0, err
+		}
+
+		if start >= upper {
+			upper = middle
+
+			continue
+		}
+
+		line, err := bufio.NewReader(io.NewSectionReader(db.file, start, db.size-start)).ReadSlice('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("reading breach database: %w", err)
+		}
+
+		key, count, err := parseLine(line)
+		if err != nil {
+			return 0, err
+		}
+
+		switch bytes.Compare(bytes.ToUpper(key), target) {
+		case 0:
+			return count, nil
+		case -1:
+			lower = start + int64(len(line))
+		default:
+			upper = start
+		}
+	}
+
+	return 0, nil
+}
+
+// lineStart returns the offset of the first line starting at or after offset.
+func (db *DB) lineStart(lower, offset int64) (int64, error) {
+	if offset == lower {
+		return offset, nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(db.file, offset-1, db.size-offset+1))
+
+	skipped, err := reader.ReadSlice('\n')
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return db.size, nil
+	case errors.Is(err, bufio.ErrBufferFull):
+		return 0, fmt.Errorf("%w: line too long", ErrFormat)
+	case err != nil:
+		return 0, fmt.Errorf("reading breach database: %w", err)
+	}
+
+	return offset - 1 + int64(len(skipped)), nil
+}
+
+// parseLine parses a line of the form "HASH:COUNT", with an optional trailing carriage return.
+func parseLine(line []byte) ([]byte, uint32, error) {
+	line = bytes.TrimRight(line, "\r\n")
+
+	digest, count, found := bytes.Cut(line, []byte(":"))
+	if !found || len(digest) == 0 {
+		return nil, 0, fmt.Errorf("%w: expected lines of the form HASH:COUNT, got %q", ErrFormat, line)
+	}
+
+	var value uint32
+
+	for _, char := range count {
+		if char < '0' || char > '9' {
+			return nil, 0, fmt.Errorf("%w: invalid count in line %q", ErrFormat, line)
+		}
+
+		value = value*10 + uint32(char-'0') //nolint:mnd	// Decimal digits.
+	}
+
+	return digest, value, nil
+}
@@ -0,0 +1,165 @@
+package breach_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/breach"
+)
+
+// TestSum checks the hashes of "password" against their well-known values.
+func TestSum(t *testing.T) {
+	t.Parallel()
+
+	tests := map[breach.Hash]string{
+		breach.SHA1: "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+		breach.NTLM: "8846F7EAEE8FB117AD06BDD830B7586C",
+	}
+
+	for hash, want := range tests {
+		if got := strings.ToUpper(hex.EncodeToString(hash.Sum("password"))); got != want {
+			t.Errorf("%s: Sum(password) = %s, want %s", hash, got, want)
+		}
+	}
+}
+
+// database writes a text database of the passwords p0 to p(n-1), where pi appears i+1 times,
+// with the given line ending, and returns its path.
+func database(t *testing.T, hash breach.Hash, n int, ending string) string {
+	t.Helper()
+
+	lines := make([]string, 0, n)
+
+	for i := range n {
+		digest := strings.ToUpper(hex.EncodeToString(hash.Sum("p" + strconv.Itoa(i))))
+
+		lines = append(lines, fmt.Sprintf("%s:%d", digest, i+1))
+	}
+
+	slices.Sort(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, ending)+ending), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// index builds a binary index of the text database at the path and returns its path.
+func index(t *testing.T, path string) string {
+	t.Helper()
+
+	source, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	var destination bytes.Buffer
+
+	if _, err := breach.BuildIndex(source, &destination); err != nil {
+		t.Fatal(err)
+	}
+
+	indexed := path + ".bin"
+
+	if err := os.WriteFile(indexed, destination.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return indexed
+}
+
+// TestLookup finds every password of text databases and their indexes, and none of the absent ones.
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	const n = 500
+
+	for _, hash := range []breach.Hash{breach.SHA1, breach.NTLM} {
+		for _, ending := range []string{"\n", "\r\n"} {
+			text := database(t, hash, n, ending)
+
+			for _, path := range []string{text, index(t, text)} {
+				db, err := breach.Open(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if db.Hash() != hash {
+					t.Errorf("%s: Hash() = %s, want %s", path, db.Hash(), hash)
+				}
+
+				for i := range n + 100 {
+					want := uint32(0)
+					if i < n {
+						want = uint32(i + 1)
+					}
+
+					count, err := db.Lookup("p" + strconv.Itoa(i))
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if count != want {
+						t.Errorf("%s %q: Lookup(p%d) = %d, want %d", hash, ending, i, count, want)
+					}
+				}
+
+				db.Close()
+			}
+		}
+	}
+}
+
+// TestBuildIndexErrors rejects unordered, malformed and empty databases.
+func TestBuildIndexErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"unordered":     "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:1\n00000000000000000000000000000000:1\n",
+		"duplicated":    "00000000000000000000000000000000:1\n00000000000000000000000000000000:2\n",
+		"missing count": "00000000000000000000000000000000\n",
+		"invalid count": "00000000000000000000000000000000:x\n",
+		"invalid hash":  "0000000000000000000000000000000G:1\n",
+		"mixed hashes":  "00000000000000000000000000000000:1\n0000000000000000000000000000000000000000:1\n",
+		"unknown hash":  "0000:1\n",
+		"empty":         "\n",
+	}
+
+	for name, content := range tests {
+		if _, err := breach.BuildIndex(strings.NewReader(content), &bytes.Buffer{}); !errors.Is(err, breach.ErrFormat) {
+			t.Errorf("%s: error = %v, want %v", name, err, breach.ErrFormat)
+		}
+	}
+}
+
+// TestOpenTruncatedIndex rejects indexes that do not consist of whole records.
+func TestOpenTruncatedIndex(t *testing.T) {
+	t.Parallel()
+
+	indexed := index(t, database(t, breach.SHA1, 10, "\n"))
+
+	content, err := os.ReadFile(indexed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(indexed, content[:len(content)-1], 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := breach.Open(indexed); !errors.Is(err, breach.ErrFormat) {
+		t.Errorf("error = %v, want %v", err, breach.ErrFormat)
+	}
+}
@@ -0,0 +1,161 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// magic identifies binary breach indexes.
+	magic = "GOGENBRI"
+
+	// version is the version of the binary index format.
+	version = 1
+
+	// headerSize is the size of the header: magic, version, hash function and reserved bytes.
+	headerSize = 16
+
+	// countSize is the size of the big-endian occurrence count following each digest.
+	countSize = 4
+)
+
+// BuildIndex converts a text database, ordered by hash, into a binary index.
+// The index consists of a 16-byte header followed by fixed-size records of the raw digest
+// and a big-endian uint32 count, which allows lookups with a plain binary search.
+// It returns the number of records written.
+func BuildIndex(source io.Reader, destination io.Writer) (int64, error) {
+	scanner := bufio.NewScanner(source)
+	writer := bufio.NewWriter(destination)
+
+	var (
+		hash     Hash
+		previous []byte
+		records  int64
+	)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		key, count, err := parseLine(line)
+		if err != nil {
+			return records, err
+		}
+
+		if hash == 0 {
+			if hash, err = hashFromHexLength(len(key)); err != nil {
+				return records, fmt.Errorf("%w: %w", ErrFormat, err)
+			}
+
+			if err := writeHeader(writer, hash); err != nil {
+				return records, err
+			}
+		}
+
+		digest := make([]byte, hash.Size())
+
+		if len(key) != hex.EncodedLen(hash.Size()) {
+			return records, fmt.Errorf("%w: hashes of different lengths, see line %q", ErrFormat, line)
+		}
+
+		if _, err := hex.Decode(digest, key); err != nil {
+			return records, fmt.Errorf("%w: invalid hash in line %q", ErrFormat, line)
+		}
+
+		if previous != nil && bytes.Compare(previous, digest) >= 0 {
+			return records, fmt.Errorf("%w: lines must be ordered by hash, see line %q", ErrFormat, line)
+		}
+
+		previous = digest
+
+		if _, err := writer.Write(binary.BigEndian.AppendUint32(digest, count)); err != nil {
+			return records, fmt.Errorf("writing breach index: %w", err)
+		}
+
+		records++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return records, fmt.Errorf("reading breach database: %w", err)
+	}
+
+	if hash == 0 {
+		return 0, fmt.Errorf("%w: no hashes found", ErrFormat)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return records, fmt.Errorf("writing breach index: %w", err)
+	}
+
+	return records, nil
+}
+
+// writeHeader writes the header of a binary index.
+func writeHeader(writer io.Writer, hash Hash) error {
+	header := make([]byte, headerSize)
+
+	copy(header, magic)
+	header[len(magic)] = version
+	header[len(magic)+1] = byte(hash)
+
+	if _, err := writer.Write(header); err != nil {
+		return fmt.Errorf("writing breach index: %w", err)
+	}
+
+	return nil
+}
+
+// openIndex prepares searching a binary index with the given header.
+func (db *DB) openIndex(header []byte) error {
+	if header[len(magic)] != version {
+		return fmt.Errorf("%w: unsupported index version %d", ErrFormat, header[len(magic)])
+	}
+
+	db.hash = Hash(header[len(magic)+1])
+
+	if db.hash.Size() == 0 {
+		return fmt.Errorf("%w: unsupported hash function %d", ErrFormat, header[len(magic)+1])
+	}
+
+	if (db.size-headerSize)%int64(db.hash.Size()+countSize) != 0 {
+		return fmt.Errorf("%w: truncated index", ErrFormat)
+	}
+
+	db.search = db.searchIndex
+
+	return nil
+}
+
+// searchIndex performs a binary search over the records of a binary index.
+func (db *DB) searchIndex(digest []byte) (uint32, error) {
+	size := int64(db.hash.Size() + countSize)
+	record := make([]byte, size)
+
+	lower, upper := int64(0), (db.size-headerSize)/size
+
+	for lower < upper {
+		middle := lower + (upper-lower)/2
+
+		if _, err := db.file.ReadAt(record, headerSize+middle*size); err != nil && !errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("reading breach index: %w", err)
+		}
+
+		switch bytes.Compare(record[:db.hash.Size()], digest) {
+		case 0:
+			return binary.BigEndian.Uint32(record[db.hash.Size():]), nil
+		case -1:
+			lower = middle + 1
+		default:
+			upper = middle
+		}
+	}
+
+	return 0, nil
+}
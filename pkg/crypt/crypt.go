@@ -0,0 +1,322 @@
+// Package crypt provides authenticated encryption of streams with AES-256-GCM or XChaCha20-Poly1305.
+//
+// Encrypted streams are self-describing envelopes: a versioned header naming the cipher and
+// key derivation, followed by the plaintext sealed in fixed-size chunks. Every chunk is
+// authenticated together with the header, its position and whether it is the last one,
+// so reordered, truncated or extended ciphertexts are detected while arbitrarily large
+// inputs are processed in constant memory.
+//
+// Example usage:
+//
+//	writer, err := crypt.NewWriter(destination, key, crypt.AES256GCM)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if _, err := io.Copy(writer, source); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if err := writer.Close(); err != nil {
+//	    log.Fatal(err)
+//	}
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/idelchi/gogen/pkg/argon"
+)
+
+// ErrAuthentication indicates a ciphertext that was modified, truncated or decrypted with the wrong key.
+var ErrAuthentication = errors.New("authentication failed: wrong key or modified data")
+
+// Cipher is an AEAD used to seal the chunks.
+type Cipher byte
+
+const (
+	// AES256GCM is AES-256 in Galois/Counter mode.
+	AES256GCM Cipher = 1
+	// XChaCha20Poly1305 is XChaCha20-Poly1305.
+	XChaCha20Poly1305 Cipher = 2
+)
+
+// ParseCipher returns the cipher with the given name (aes-256-gcm, xchacha20-poly1305).
+func ParseCipher(name string) (Cipher, error) {
+	for _, cipher := range []Cipher{AES256GCM, XChaCha20Poly1305} {
+		if cipher.String() == name {
+			return cipher, nil
+		}
+	}
+
+	//nolint:err113 // Occasional dynamic errors are fine.
+	return 0, fmt.Errorf("unsupported cipher %q", name)
+}
+
+// String returns the name of the cipher.
+func (c Cipher) String() string {
+	switch c {
+	case AES256GCM:
+		return "aes-256-gcm"
+	case XChaCha20Poly1305:
+		return "xchacha20-poly1305"
+	default:
+		return fmt.Sprintf("cipher(%d)", byte(c))
+	}
+}
+
+// aead returns the AEAD of the cipher with the given key.
+func (c Cipher) aead(key []byte) (cipher.AEAD, error) {
+	switch c {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("creating cipher: %w", err)
+		}
+
+		return cipher.NewGCM(block) //nolint:wrapcheck	// Error does not need additional wrapping.
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(key) //nolint:wrapcheck	// Error does not need additional wrapping.
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported cipher %d", byte(c))
+	}
+}
+
+// stream seals or opens the chunks of an envelope, following the STREAM construction:
+// the nonce of a chunk is its counter followed by a flag marking the last chunk.
+type stream struct {
+	aead    cipher.AEAD
+	header  []byte
+	counter uint32
+}
+
+// newStream returns the stream of the header, with the file key derived from the secret.
+func newStream(header Header, secret []byte) (*stream, error) {
+	key, err := header.fileKey(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := header.Cipher.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stream{aead: aead, header: header.marshal()}, nil
+}
+
+// nonce returns the nonce of the current chunk.
+func (s *stream) nonce(last bool) ([]byte, error) {
+	if s.counter == ^uint32(0) {
+		return nil, errors.New("too many chunks") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+
+	binary.BigEndian.PutUint32(nonce[len(nonce)-5:], s.counter)
+
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+
+	s.counter++
+
+	return nonce, nil
+}
+
+// Writer encrypts everything written to it into an envelope.
+// Close must be called to seal the last chunk.
+type Writer struct {
+	destination io.Writer
+	stream      *stream
+	buffer      []byte
+	closed      bool
+}
+
+// NewWriter writes the header of an envelope encrypted with the 32-byte key to the destination
+// and returns the writer of the plaintext.
+func NewWriter(destination io.Writer, key []byte, cipher Cipher) (*Writer, error) {
+	header, err := newHeader(cipher, RawKey, argon.Params{})
+	if err != nil {
+		return nil, err
+	}
+
+	return newWriter(destination, header, key)
+}
+
+// NewPassphraseWriter is like NewWriter, but derives the key from the passphrase with Argon2id.
+func NewPassphraseWriter(destination io.Writer, passphrase []byte, cipher Cipher, params argon.Params) (*Writer, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	header, err := newHeader(cipher, Argon2id, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWriter(destination, header, passphrase)
+}
+
+// newWriter writes the header and returns the writer of the plaintext.
+func newWriter(destination io.Writer, header Header, secret []byte) (*Writer, error) {
+	stream, err := newStream(header, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := destination.Write(stream.header); err != nil {
+		return nil, fmt.Errorf("writing header: %w", err)
+	}
+
+	return &Writer{destination: destination, stream: stream, buffer: make([]byte, 0, header.ChunkSize)}, nil
+}
+
+// Write encrypts the data. A chunk is only sealed once more data follows it,
+// so that the last chunk can be marked as such by Close.
+func (w *Writer) Write(data []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to closed writer") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	written := 0
+
+	for len(data) > 0 {
+		if len(w.buffer) == cap(w.buffer) {
+			if err := w.flush(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(w.buffer[len(w.buffer):cap(w.buffer)], data)
+
+		w.buffer = w.buffer[:len(w.buffer)+n]
+		data = data[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close seals the last chunk. It does not close the destination.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	return w.flush(true)
+}
+
+// flush seals the buffered chunk and writes it to the destination.
+func (w *Writer) flush(last bool) error {
+	nonce, err := w.stream.nonce(last)
+	if err != nil {
+		return err
+	}
+
+	sealed := w.stream.aead.Seal(nil, nonce, w.buffer, w.stream.header)
+	w.buffer = w.buffer[:0]
+
+	if _, err := w.destination.Write(sealed); err != nil {
+		return fmt.Errorf("writing chunk: %w", err)
+	}
+
+	return nil
+}
+
+// Reader decrypts an envelope. Data is only returned after its chunk was authenticated;
+// an error wrapping ErrAuthentication is returned for modified, truncated or extended envelopes.
+type Reader struct {
+	source  io.Reader
+	stream  *stream
+	chunk   []byte
+	pending []byte
+	peeked  []byte
+	done    bool
+}
+
+// NewReader returns the reader of the plaintext of the envelope with the given header,
+// as returned by ReadHeader, using the key or, if the header NeedsPassphrase, the passphrase.
+func NewReader(source io.Reader, header Header, secret []byte) (*Reader, error) {
+	stream, err := newStream(header, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(header.ChunkSize) + stream.aead.Overhead()
+
+	return &Reader{source: source, stream: stream, chunk: make([]byte, size)}, nil
+}
+
+// Read returns decrypted and authenticated data.
+func (r *Reader) Read(data []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(data, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+// next reads, authenticates and decrypts the next chunk.
+// A chunk is the last one if it is shorter than a full chunk, or if no data follows it.
+func (r *Reader) next() error {
+	copy(r.chunk, r.peeked)
+
+	n, err := io.ReadFull(r.source, r.chunk[len(r.peeked):])
+	n += len(r.peeked)
+	r.peeked = nil
+
+	var last bool
+
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return fmt.Errorf("reading chunk: %w", err)
+	default:
+		peek := make([]byte, 1)
+
+		switch _, err := io.ReadFull(r.source, peek); {
+		case errors.Is(err, io.EOF):
+			last = true
+		case err != nil:
+			return fmt.Errorf("reading chunk: %w", err)
+		default:
+			r.peeked = peek
+		}
+	}
+
+	nonce, err := r.stream.nonce(last)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := r.stream.aead.Open(r.chunk[:0], nonce, r.chunk[:n], r.stream.header)
+	if err != nil {
+		return fmt.Errorf("%w: chunk %d", ErrAuthentication, r.stream.counter-1)
+	}
+
+	r.pending = plaintext
+	r.done = last
+
+	return nil
+}
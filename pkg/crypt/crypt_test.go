@@ -0,0 +1,288 @@
+package crypt_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/idelchi/gogen/pkg/argon"
+	"github.com/idelchi/gogen/pkg/crypt"
+)
+
+// Known envelopes of the plaintext "gogen", to detect changes of the format.
+const (
+	// knownAES is encrypted with AES-256-GCM and the key 0x42 repeated 32 times.
+	knownAES = "474f47454e454e43010100000100003516b6098bfe323dd4e8446c5b927ddffa1c6656c026d2fceffd94301bf6a344" +
+		"8884b13324355b951c19218b5b6a30c1002d8120c3"
+
+	// knownXChaCha is encrypted with XChaCha20-Poly1305 and the key 0x42 repeated 32 times.
+	knownXChaCha = "474f47454e454e4301020000010000c2c07773cb8f1cc1093b4b0c5fb2a70500c13c44142eaea3a56747c8e83d5e" +
+		"af45f917f61685a681bbc7ee17d2616238b2b65a0f5a"
+
+	// knownPassphrase is encrypted with AES-256-GCM and the passphrase "correct horse",
+	// with Argon2id using 1 iteration, 8 KiB and 1 lane.
+	knownPassphrase = "474f47454e454e4301010100010000131cdffaf9ea048666d8b4c0e6fc02073bbbdd211de95c5f6518ead7d2" +
+		"0065a4000000010000000801cc85ba23d6bcdd6581799bd64cf7eb2f89052bf12f"
+)
+
+// key is the key of the known envelopes.
+func key() []byte {
+	return bytes.Repeat([]byte{0x42}, 32)
+}
+
+// decrypt decrypts the envelope with the key or passphrase.
+func decrypt(envelope, secret []byte) ([]byte, error) {
+	source := bytes.NewReader(envelope)
+
+	header, err := crypt.ReadHeader(source)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := crypt.NewReader(source, header, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(reader)
+}
+
+// encrypt encrypts the plaintext with the key.
+func encrypt(t *testing.T, plaintext []byte, cipher crypt.Cipher) []byte {
+	t.Helper()
+
+	var envelope bytes.Buffer
+
+	writer, err := crypt.NewWriter(&envelope, key(), cipher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := writer.Write(plaintext); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return envelope.Bytes()
+}
+
+// TestDecryptKnown decrypts the known envelopes.
+func TestDecryptKnown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		envelope string
+		secret   []byte
+	}{
+		{"aes-256-gcm", knownAES, key()},
+		{"xchacha20-poly1305", knownXChaCha, key()},
+		{"passphrase", knownPassphrase, []byte("correct horse")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			envelope, err := hex.DecodeString(test.envelope)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plaintext, err := decrypt(envelope, test.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(plaintext) != "gogen" {
+				t.Errorf("plaintext = %q, want %q", plaintext, "gogen")
+			}
+		})
+	}
+}
+
+// TestKnownConstruction opens the known AES-256-GCM envelope without this package, following the specification:
+// the file key is HKDF-SHA256 of the key with the salt, and the only chunk is sealed with the header as
+// additional data and a nonce of the zero counter followed by the last chunk flag.
+func TestKnownConstruction(t *testing.T) {
+	t.Parallel()
+
+	envelope, err := hex.DecodeString(knownAES)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const headerSize = 8 + 3 + 4 + 32
+
+	header, sealed := envelope[:headerSize], envelope[headerSize:]
+	salt := header[headerSize-32:]
+
+	fileKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key(), salt, []byte("gogen/encrypt/v1")), fileKey); err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	nonce[len(nonce)-1] = 1
+
+	plaintext, err := aead.Open(nil, nonce, sealed, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != "gogen" {
+		t.Errorf("plaintext = %q, want %q", plaintext, "gogen")
+	}
+}
+
+// TestRoundTrip encrypts and decrypts plaintexts around the chunk boundaries with both ciphers.
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	sizes := []int{0, 1, crypt.ChunkSize - 1, crypt.ChunkSize, crypt.ChunkSize + 1, 3 * crypt.ChunkSize}
+
+	for _, cipher := range []crypt.Cipher{crypt.AES256GCM, crypt.XChaCha20Poly1305} {
+		for _, size := range sizes {
+			plaintext := make([]byte, size)
+			if _, err := rand.Read(plaintext); err != nil {
+				t.Fatal(err)
+			}
+
+			decrypted, err := decrypt(encrypt(t, plaintext, cipher), key())
+			if err != nil {
+				t.Fatalf("%s, %d bytes: %v", cipher, size, err)
+			}
+
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("%s, %d bytes: decrypted plaintext differs", cipher, size)
+			}
+		}
+	}
+}
+
+// TestPassphraseRoundTrip encrypts and decrypts with a passphrase.
+func TestPassphraseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var envelope bytes.Buffer
+
+	params := argon.Params{Iterations: 1, Memory: 8, Parallelism: 1}
+
+	writer, err := crypt.NewPassphraseWriter(&envelope, []byte("correct horse"), crypt.XChaCha20Poly1305, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := writer.Write([]byte("gogen")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := decrypt(envelope.Bytes(), []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != "gogen" {
+		t.Errorf("plaintext = %q, want %q", plaintext, "gogen")
+	}
+
+	if _, err := decrypt(envelope.Bytes(), []byte("wrong horse")); !errors.Is(err, crypt.ErrAuthentication) {
+		t.Errorf("wrong passphrase: error = %v, want %v", err, crypt.ErrAuthentication)
+	}
+}
+
+// TestTampering detects truncated, extended, reordered and modified envelopes and wrong keys.
+func TestTampering(t *testing.T) {
+	t.Parallel()
+
+	plaintext := make([]byte, 3*crypt.ChunkSize)
+
+	envelope := encrypt(t, plaintext, crypt.AES256GCM)
+
+	const (
+		headerSize = 8 + 3 + 4 + 32
+		chunk      = crypt.ChunkSize + 16
+	)
+
+	swapped := bytes.Clone(envelope)
+	copy(swapped[headerSize:], envelope[headerSize+chunk:headerSize+2*chunk])
+	copy(swapped[headerSize+chunk:], envelope[headerSize:headerSize+chunk])
+
+	modified := bytes.Clone(envelope)
+	modified[len(modified)/2] ^= 1
+
+	header := bytes.Clone(envelope)
+	header[headerSize-1] ^= 1
+
+	wrong := bytes.Repeat([]byte{0x43}, 32)
+
+	tests := []struct {
+		name     string
+		envelope []byte
+		key      []byte
+	}{
+		{"truncated at a chunk boundary", envelope[:headerSize+2*chunk], key()},
+		{"truncated within a chunk", envelope[:len(envelope)-1], key()},
+		{"extended", append(bytes.Clone(envelope), 0), key()},
+		{"reordered", swapped, key()},
+		{"modified", modified, key()},
+		{"modified salt", header, key()},
+		{"wrong key", envelope, wrong},
+	}
+
+	for _, test := range tests {
+		if _, err := decrypt(test.envelope, test.key); !errors.Is(err, crypt.ErrAuthentication) {
+			t.Errorf("%s: error = %v, want %v", test.name, err, crypt.ErrAuthentication)
+		}
+	}
+
+	if _, err := decrypt([]byte("not an envelope at all, but long enough to hold a header"), key()); !errors.Is(err, crypt.ErrFormat) {
+		t.Errorf("not an envelope: error = %v, want %v", err, crypt.ErrFormat)
+	}
+}
+
+// TestExcessiveParameters rejects headers whose Argon2id parameters would stall decryption.
+func TestExcessiveParameters(t *testing.T) {
+	t.Parallel()
+
+	envelope, err := hex.DecodeString(knownPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const params = 8 + 3 + 4 + 32
+
+	for name, offset := range map[string]int{"iterations": params, "memory": params + 4} {
+		crafted := bytes.Clone(envelope)
+		copy(crafted[offset:], []byte{0xff, 0xff, 0xff, 0xff})
+
+		if _, err := crypt.ReadHeader(bytes.NewReader(crafted)); !errors.Is(err, crypt.ErrFormat) {
+			t.Errorf("%s: error = %v, want %v", name, err, crypt.ErrFormat)
+		}
+	}
+}
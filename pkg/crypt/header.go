@@ -0,0 +1,196 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/idelchi/gogen/pkg/argon"
+)
+
+// ErrFormat indicates input that is not a valid encrypted envelope.
+var ErrFormat = errors.New("invalid envelope")
+
+const (
+	// magic identifies encrypted envelopes.
+	magic = "GOGENENC"
+
+	// version is the version of the envelope format.
+	version = 1
+
+	// saltSize is the size of the per-file salt.
+	saltSize = 32
+
+	// keySize is the size of keys and derived file keys.
+	keySize = 32
+
+	// ChunkSize is the size of the plaintext chunks.
+	ChunkSize = 64 * 1024
+
+	// maxChunkSize bounds the chunk size accepted when decrypting, to bound memory usage.
+	maxChunkSize = 16 * 1024 * 1024
+
+	// maxMemory bounds the Argon2id memory in KiB accepted when decrypting.
+	maxMemory = 4 * 1024 * 1024
+
+	// maxIterations bounds the Argon2id passes accepted when decrypting,
+	// so a crafted header cannot stall decryption before authentication.
+	maxIterations = 64
+
+	// info domain-separates the derivation of file keys.
+	info = "gogen/encrypt/v1"
+)
+
+// KDF identifies how the key of an envelope is obtained.
+type KDF byte
+
+const (
+	// RawKey uses a key given as bytes.
+	RawKey KDF = 0
+	// Argon2id derives the key from a passphrase with Argon2id.
+	Argon2id KDF = 1
+)
+
+// Header is the self-describing, authenticated header of an envelope.
+//
+// Layout (big-endian):
+//
+//	magic "GOGENENC" | version (1) | cipher (1) | kdf (1) | chunk size (4) | salt (32)
+//	[kdf argon2id: iterations (4) | memory KiB (4) | parallelism (1)]
+type Header struct {
+	// Cipher is the AEAD of the chunks
+	Cipher Cipher
+
+	// KDF identifies how the key is obtained
+	KDF KDF
+
+	// ChunkSize is the size of the plaintext chunks
+	ChunkSize uint32
+
+	// Salt is the random per-file salt of the key derivation
+	Salt []byte
+
+	// Params are the Argon2id parameters, if the key is derived from a passphrase
+	Params argon.Params
+}
+
+// newHeader returns a header with a fresh random salt.
+func newHeader(cipher Cipher, kdf KDF, params argon.Params) (Header, error) {
+	if _, err := cipher.aead(make([]byte, keySize)); err != nil {
+		return Header{}, err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return Header{}, fmt.Errorf("generating salt: %w", err)
+	}
+
+	return Header{Cipher: cipher, KDF: kdf, ChunkSize: ChunkSize, Salt: salt, Params: params}, nil
+}
+
+// marshal encodes the header.
+func (h Header) marshal() []byte {
+	buffer := bytes.NewBufferString(magic)
+
+	buffer.WriteByte(version)
+	buffer.WriteByte(byte(h.Cipher))
+	buffer.WriteByte(byte(h.KDF))
+	_ = binary.Write(buffer, binary.BigEndian, h.ChunkSize)
+	buffer.Write(h.Salt)
+
+	if h.KDF == Argon2id {
+		_ = binary.Write(buffer, binary.BigEndian, h.Params.Iterations)
+		_ = binary.Write(buffer, binary.BigEndian, h.Params.Memory)
+		buffer.WriteByte(h.Params.Parallelism)
+	}
+
+	return buffer.Bytes()
+}
+
+// ReadHeader reads and validates the header of an envelope.
+func ReadHeader(reader io.Reader) (Header, error) {
+	fixed := make([]byte, len(magic)+3+4+saltSize) //nolint:mnd	// Version, cipher, kdf and chunk size.
+	if _, err := io.ReadFull(reader, fixed); err != nil {
+		return Header{}, fmt.Errorf("%w: reading header: %w", ErrFormat, err)
+	}
+
+	if string(fixed[:len(magic)]) != magic {
+		return Header{}, fmt.Errorf("%w: not an encrypted file", ErrFormat)
+	}
+
+	fields := fixed[len(magic):]
+
+	if fields[0] != version {
+		return Header{}, fmt.Errorf("%w: unsupported version %d", ErrFormat, fields[0])
+	}
+
+	header := Header{
+		Cipher:    Cipher(fields[1]),
+		KDF:       KDF(fields[2]),
+		ChunkSize: binary.BigEndian.Uint32(fields[3:7]),
+		Salt:      fields[7:],
+	}
+
+	if _, err := header.Cipher.aead(make([]byte, keySize)); err != nil {
+		return Header{}, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	if header.ChunkSize == 0 || header.ChunkSize > maxChunkSize {
+		return Header{}, fmt.Errorf("%w: chunk size %d out of range", ErrFormat, header.ChunkSize)
+	}
+
+	switch header.KDF {
+	case RawKey:
+	case Argon2id:
+		params := make([]byte, 4+4+1) //nolint:mnd	// Iterations, memory and parallelism.
+		if _, err := io.ReadFull(reader, params); err != nil {
+			return Header{}, fmt.Errorf("%w: reading header: %w", ErrFormat, err)
+		}
+
+		header.Params = argon.Params{
+			Iterations:  binary.BigEndian.Uint32(params[0:4]),
+			Memory:      binary.BigEndian.Uint32(params[4:8]),
+			Parallelism: params[8],
+		}
+
+		if header.Params.Iterations == 0 || header.Params.Iterations > maxIterations ||
+			header.Params.Parallelism == 0 || header.Params.Memory > maxMemory {
+			return Header{}, fmt.Errorf("%w: argon2id parameters out of range", ErrFormat)
+		}
+	default:
+		return Header{}, fmt.Errorf("%w: unsupported key derivation %d", ErrFormat, header.KDF)
+	}
+
+	return header, nil
+}
+
+// NeedsPassphrase reports whether the key of the envelope is derived from a passphrase.
+func (h Header) NeedsPassphrase() bool {
+	return h.KDF == Argon2id
+}
+
+// fileKey derives the key of the chunks from the key or passphrase, bound to the salt of the file.
+// Keys and passphrases can therefore be reused across files without reusing nonces.
+func (h Header) fileKey(secret []byte) ([]byte, error) {
+	master := secret
+
+	if h.KDF == Argon2id {
+		master = argon.Key(secret, h.Salt, keySize, h.Params)
+	} else if len(secret) != keySize {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(secret))
+	}
+
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, h.Salt, []byte(info)), key); err != nil {
+		return nil, fmt.Errorf("deriving file key: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,129 @@
+package digest
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrFormat indicates a malformed line in a checksum file.
+var ErrFormat = errors.New("improperly formatted checksum line")
+
+// Entry is a line of a checksum file.
+type Entry struct {
+	// Digest is the expected hex-encoded digest, in lowercase
+	Digest string
+
+	// Path is the path of the file
+	Path string
+
+	// Line is the line number in the checksum file
+	Line int
+}
+
+// FormatLine formats a digest and path as a line of a checksum file ("<digest>  <path>\n").
+// Like sha256sum, paths containing a backslash or newline are escaped and the line is prefixed with a backslash.
+func FormatLine(digest, path string) string {
+	if !strings.ContainsAny(path, "\\\n\r") {
+		return digest + "  " + path + "\n"
+	}
+
+	escaped := strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r").Replace(path)
+
+	return "\\" + digest + "  " + escaped + "\n"
+}
+
+// ParseCheckFile reads the entries of a checksum file as written by sha256sum, b2sum or FormatLine.
+// Both the text ("<digest>  <path>") and the binary ("<digest> *<path>") modes are accepted.
+// Empty lines and comments ("#") are skipped; every digest must have the size of the algorithm.
+func ParseCheckFile(reader io.Reader, algorithm Algorithm) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(reader)
+
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entry, err := parseLine(line, algorithm)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		entry.Line = number
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading checksum file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// parseLine parses a single line of a checksum file.
+func parseLine(line string, algorithm Algorithm) (Entry, error) {
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+
+	digest, path, found := strings.Cut(line, " ")
+	if !found || path == "" || (path[0] != ' ' && path[0] != '*') || len(path) < 2 {
+		return Entry{}, ErrFormat
+	}
+
+	path = path[1:]
+
+	if escaped {
+		var err error
+
+		if path, err = unescape(path); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	decoded, err := hex.DecodeString(digest)
+	if err != nil || len(decoded) != algorithm.Size() {
+		return Entry{}, fmt.Errorf("%w: expected a %s digest of %d hex characters", ErrFormat, algorithm, 2*algorithm.Size())
+	}
+
+	return Entry{Digest: strings.ToLower(digest), Path: path}, nil
+}
+
+// unescape reverses the escaping of FormatLine.
+func unescape(path string) (string, error) {
+	var result strings.Builder
+
+	for index := 0; index < len(path); index++ {
+		if path[index] != '\\' {
+			result.WriteByte(path[index])
+
+			continue
+		}
+
+		if index++; index == len(path) {
+			return "", fmt.Errorf("%w: trailing backslash", ErrFormat)
+		}
+
+		switch path[index] {
+		case '\\':
+			result.WriteByte('\\')
+		case 'n':
+			result.WriteByte('\n')
+		case 'r':
+			result.WriteByte('\r')
+		default:
+			return "", fmt.Errorf("%w: invalid escape sequence \\%c", ErrFormat, path[index])
+		}
+	}
+
+	return result.String(), nil
+}
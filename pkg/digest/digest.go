@@ -0,0 +1,179 @@
+// Package digest provides streaming cryptographic digests of files and readers,
+// and reading and writing of checksum files compatible with sha256sum and friends.
+//
+// Example usage:
+//
+//	sums := digest.Files([]string{"a.tar.gz", "b.tar.gz"}, digest.SHA256, 4)
+//
+//	for _, sum := range sums {
+//	    fmt.Print(sum)
+//	}
+package digest
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/sha3"
+	"lukechampine.com/blake3"
+)
+
+// Algorithm is a digest algorithm.
+type Algorithm string
+
+const (
+	// SHA256 is SHA-256.
+	SHA256 Algorithm = "sha256"
+	// SHA384 is SHA-384.
+	SHA384 Algorithm = "sha384"
+	// SHA512 is SHA-512.
+	SHA512 Algorithm = "sha512"
+	// SHA3256 is SHA3-256.
+	SHA3256 Algorithm = "sha3-256"
+	// SHA3384 is SHA3-384.
+	SHA3384 Algorithm = "sha3-384"
+	// SHA3512 is SHA3-512.
+	SHA3512 Algorithm = "sha3-512"
+	// BLAKE2b is BLAKE2b with a 512-bit digest, as computed by b2sum.
+	BLAKE2b Algorithm = "blake2b"
+	// BLAKE2s is BLAKE2s with a 256-bit digest.
+	BLAKE2s Algorithm = "blake2s"
+	// BLAKE3 is BLAKE3 with a 256-bit digest, as computed by b3sum.
+	BLAKE3 Algorithm = "blake3"
+)
+
+// Algorithms returns the supported algorithms.
+func Algorithms() []Algorithm {
+	return []Algorithm{SHA256, SHA384, SHA512, SHA3256, SHA3384, SHA3512, BLAKE2b, BLAKE2s, BLAKE3}
+}
+
+// New returns a new hash computing the digest of the algorithm.
+func (a Algorithm) New() (hash.Hash, error) {
+	const blake3Size = 32
+
+	switch a {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA384:
+		return sha512.New384(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case SHA3256:
+		return sha3.New256(), nil
+	case SHA3384:
+		return sha3.New384(), nil
+	case SHA3512:
+		return sha3.New512(), nil
+	case BLAKE2b:
+		return blake2b.New512(nil) //nolint:wrapcheck	// Error does not need additional wrapping.
+	case BLAKE2s:
+		return blake2s.New256(nil) //nolint:wrapcheck	// Error does not need additional wrapping.
+	case BLAKE3:
+		return blake3.New(blake3Size, nil), nil
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported algorithm %q, supported: %v", a, Algorithms())
+	}
+}
+
+// Size returns the size of the digest in bytes, or 0 for unsupported algorithms.
+func (a Algorithm) Size() int {
+	h, err := a.New()
+	if err != nil {
+		return 0
+	}
+
+	return h.Size()
+}
+
+// Valid reports whether the algorithm is supported.
+func (a Algorithm) Valid() bool {
+	return slices.Contains(Algorithms(), a)
+}
+
+// Reader computes the hex-encoded digest of everything read from the reader.
+// The input is streamed, so arbitrarily large inputs can be hashed.
+func Reader(reader io.Reader, algorithm Algorithm) (string, error) {
+	h, err := algorithm.New()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", fmt.Errorf("reading input: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// File computes the hex-encoded digest of the file at the path, or of stdin if the path is "-".
+func File(path string, algorithm Algorithm) (string, error) {
+	if path == Stdin {
+		return Reader(os.Stdin, algorithm)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	return Reader(file, algorithm)
+}
+
+// Stdin is the path denoting standard input.
+const Stdin = "-"
+
+// Sum is the digest of a file.
+type Sum struct {
+	// Path is the path of the file
+	Path string
+
+	// Digest is the hex-encoded digest, empty if Err is set
+	Digest string
+
+	// Err is the error hashing the file
+	Err error
+}
+
+// String formats the sum as a line of a checksum file.
+func (s Sum) String() string {
+	return FormatLine(s.Digest, s.Path)
+}
+
+// Files computes the digests of the files using the given number of parallel workers.
+// The sums are returned in the order of the paths.
+func Files(paths []string, algorithm Algorithm, workers int) []Sum {
+	sums := make([]Sum, len(paths))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range max(1, min(workers, len(paths))) {
+		wg.Go(func() {
+			for index := range indices {
+				digest, err := File(paths[index], algorithm)
+
+				sums[index] = Sum{Path: paths[index], Digest: digest, Err: err}
+			}
+		})
+	}
+
+	for index := range paths {
+		indices <- index
+	}
+
+	close(indices)
+	wg.Wait()
+
+	return sums
+}
@@ -0,0 +1,165 @@
+package digest_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/digest"
+)
+
+// abc holds the digests of "abc" from the test vectors of the respective specifications.
+//
+//nolint:gochecknoglobals	// Constant test vectors.
+var abc = map[digest.Algorithm]string{
+	digest.SHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	digest.SHA384: "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed" +
+		"8086072ba1e7cc2358baeca134c825a7",
+	digest.SHA512: "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a" +
+		"2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+	digest.SHA3256: "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+	digest.SHA3384: "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b2" +
+		"98d88cea927ac7f539f1edf228376d25",
+	digest.SHA3512: "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e" +
+		"10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0",
+	digest.BLAKE2b: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d1" +
+		"7d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+	digest.BLAKE2s: "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982",
+	digest.BLAKE3:  "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+}
+
+// TestReader checks the digests of every algorithm against known answers.
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	for _, algorithm := range digest.Algorithms() {
+		got, err := digest.Reader(strings.NewReader("abc"), algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != abc[algorithm] {
+			t.Errorf("%s: digest = %s, want %s", algorithm, got, abc[algorithm])
+		}
+
+		if algorithm.Size() != len(abc[algorithm])/2 {
+			t.Errorf("%s: Size() = %d", algorithm, algorithm.Size())
+		}
+	}
+
+	if _, err := digest.Reader(strings.NewReader("abc"), "md5"); err == nil {
+		t.Error("unsupported algorithm succeeded")
+	}
+}
+
+// TestFiles hashes files in parallel and returns the sums in the order of the paths.
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var paths []string
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	paths = append(paths, filepath.Join(dir, "missing"))
+
+	sums := digest.Files(paths, digest.SHA256, 3)
+
+	for index, sum := range sums[:len(sums)-1] {
+		if sum.Path != paths[index] || sum.Digest != abc[digest.SHA256] || sum.Err != nil {
+			t.Errorf("sum %d = %+v", index, sum)
+		}
+	}
+
+	if missing := sums[len(sums)-1]; missing.Err == nil {
+		t.Errorf("missing file: sum = %+v, want an error", missing)
+	}
+}
+
+// TestCheckFile checks that the lines of checksum files match sha256sum, including escaped paths,
+// and that they are parsed back.
+func TestCheckFile(t *testing.T) {
+	t.Parallel()
+
+	sum := abc[digest.SHA256]
+
+	// The lines written by sha256sum (GNU coreutils 9) for these paths.
+	tests := []struct {
+		path string
+		line string
+	}{
+		{"sp ace", sum + "  sp ace\n"},
+		{`we\ird`, `\` + sum + `  we\\ird` + "\n"},
+		{"new\nline", `\` + sum + `  new\nline` + "\n"},
+	}
+
+	var content strings.Builder
+
+	for _, test := range tests {
+		line := digest.FormatLine(sum, test.path)
+		if line != test.line {
+			t.Errorf("FormatLine(%q) = %q, want %q", test.path, line, test.line)
+		}
+
+		content.WriteString(line)
+	}
+
+	// Binary mode, uppercase digests, CRLF line endings, comments and empty lines.
+	content.WriteString(strings.ToUpper(sum) + " *binary\r\n\n# comment\n")
+
+	entries, err := digest.ParseCheckFile(strings.NewReader(content.String()), digest.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []digest.Entry{
+		{Digest: sum, Path: "sp ace", Line: 1},
+		{Digest: sum, Path: `we\ird`, Line: 2},
+		{Digest: sum, Path: "new\nline", Line: 3},
+		{Digest: sum, Path: "binary", Line: 4},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %+v", entries, want)
+	}
+
+	for index := range want {
+		if entries[index] != want[index] {
+			t.Errorf("entry %d = %+v, want %+v", index, entries[index], want[index])
+		}
+	}
+}
+
+// TestParseCheckFileErrors rejects malformed lines.
+func TestParseCheckFileErrors(t *testing.T) {
+	t.Parallel()
+
+	sum := abc[digest.SHA256]
+
+	invalid := map[string]string{
+		"missing path":    sum + "\n",
+		"single space":    sum + " path\n",
+		"empty path":      sum + "  \n",
+		"short digest":    sum[:62] + "  path\n",
+		"other algorithm": abc[digest.SHA512] + "  path\n",
+		"invalid hex":     strings.Repeat("g", 64) + "  path\n",
+		"invalid escape":  `\` + sum + `  \t` + "\n",
+		"trailing escape": `\` + sum + `  path\` + "\n",
+	}
+
+	for name, content := range invalid {
+		if _, err := digest.ParseCheckFile(strings.NewReader(content), digest.SHA256); !errors.Is(err, digest.ErrFormat) {
+			t.Errorf("%s: error = %v, want %v", name, err, digest.ErrFormat)
+		}
+	}
+}
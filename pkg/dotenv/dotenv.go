@@ -0,0 +1,184 @@
+// Package dotenv reads and writes .env files line by line, preserving comments, blank lines and ordering.
+//
+// Assignments have the form KEY=VALUE, optionally prefixed with "export". Values may be single- or
+// double-quoted, and unquoted values may be followed by an inline comment (" #"). Values spanning
+// multiple lines are not supported.
+//
+// Example usage:
+//
+//	file, err := dotenv.Parse(reader)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	file.Lines[0].Set("secret")
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+//nolint:gochecknoglobals	// Compiled once.
+var (
+	// assignment matches an assignment, capturing the prefix up to the key, the key and the rest of the line.
+	assignment = regexp.MustCompile(`^(\s*(?:export\s+)?)([A-Za-z_][A-Za-z0-9_.]*)\s*=(.*)$`)
+
+	// bare matches values that need no quotes.
+	bare = regexp.MustCompile(`^[A-Za-z0-9_./:+=@,-]*$`)
+)
+
+// Line is a line of a .env file.
+type Line struct {
+	// Raw is the line as read, or as formatted after Set
+	Raw string
+
+	// Key is the key of an assignment, empty for comments and blank lines
+	Key string
+
+	// Value is the unquoted value of an assignment
+	Value string
+
+	// prefix is the text preceding the key, e.g. "export "
+	prefix string
+
+	// comment is the inline comment following the value, including the leading whitespace
+	comment string
+}
+
+// IsAssignment reports whether the line assigns a value to a key.
+func (l Line) IsAssignment() bool {
+	return l.Key != ""
+}
+
+// Set replaces the value of the assignment, quoting it if needed and keeping its inline comment.
+// Values containing characters other than letters, digits and _./:+=@,- are single-quoted,
+// or double-quoted if they contain a single quote or a newline.
+func (l *Line) Set(value string) {
+	l.Value = value
+	l.Raw = l.prefix + l.Key + "=" + Quote(value) + l.comment
+}
+
+// File is the content of a .env file.
+type File struct {
+	// Lines are the lines of the file, in order
+	Lines []Line
+}
+
+// Parse reads a .env file.
+func Parse(reader io.Reader) (File, error) {
+	var file File
+
+	scanner := bufio.NewScanner(reader)
+	for number := 1; scanner.Scan(); number++ {
+		line, err := parseLine(scanner.Text())
+		if err != nil {
+			return File{}, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		file.Lines = append(file.Lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return File{}, fmt.Errorf("reading .env file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Lookup returns the line assigning the key, or nil if there is none.
+func (f File) Lookup(key string) *Line {
+	for i := range f.Lines {
+		if f.Lines[i].Key == key {
+			return &f.Lines[i]
+		}
+	}
+
+	return nil
+}
+
+// Write writes the lines of the file, each terminated by a newline.
+func (f File) Write(writer io.Writer) error {
+	for _, line := range f.Lines {
+		if _, err := fmt.Fprintln(writer, line.Raw); err != nil {
+			return fmt.Errorf("writing .env file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Quote returns the value as written in a .env file, quoted if needed.
+func Quote(value string) string {
+	switch {
+	case bare.MatchString(value):
+		return value
+	case !strings.ContainsAny(value, "'\n"):
+		return "'" + value + "'"
+	default:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`).Replace(value) + `"`
+	}
+}
+
+// parseLine parses a single line, which is a comment or blank if it is not an assignment.
+func parseLine(raw string) (Line, error) {
+	match := assignment.FindStringSubmatch(raw)
+	if match == nil {
+		if trimmed := strings.TrimSpace(raw); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return Line{}, fmt.Errorf("invalid line %q: expected KEY=VALUE or a comment", raw) //nolint:err113 // Occasional dynamic errors are fine.
+		}
+
+		return Line{Raw: raw}, nil
+	}
+
+	line := Line{Raw: raw, prefix: match[1], Key: match[2]}
+	rest := strings.TrimLeft(match[3], " \t")
+
+	if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		end := closingQuote(rest)
+		if end < 0 {
+			return Line{}, fmt.Errorf("unterminated quote in value of %s", line.Key) //nolint:err113 // Occasional dynamic errors are fine.
+		}
+
+		line.Value = rest[1:end]
+		if rest[0] == '"' {
+			line.Value = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\$`, "$", `\n`, "\n").Replace(line.Value)
+		}
+
+		line.comment = rest[end+1:]
+
+		return line, nil
+	}
+
+	value := rest
+
+	if index := strings.Index(rest, " #"); index >= 0 {
+		value, line.comment = rest[:index], rest[index:]
+	} else if strings.HasPrefix(rest, "#") {
+		value, line.comment = "", " "+rest
+	}
+
+	line.Value = strings.TrimRight(value, " \t")
+
+	return line, nil
+}
+
+// closingQuote returns the index of the quote closing the value starting with a quote, or -1.
+// Double-quoted values may escape quotes with a backslash.
+func closingQuote(value string) int {
+	quote := value[0]
+
+	for i := 1; i < len(value); i++ {
+		switch {
+		case quote == '"' && value[i] == '\\':
+			i++
+		case value[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,132 @@
+package dotenv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/dotenv"
+)
+
+// TestParse checks the keys and values of the supported assignment forms.
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		line  string
+		key   string
+		value string
+	}{
+		{"KEY=value", "KEY", "value"},
+		{"export KEY=value", "KEY", "value"},
+		{"  KEY = value  ", "KEY", "value"},
+		{"KEY=", "KEY", ""},
+		{"KEY=value # comment", "KEY", "value"},
+		{"KEY=value#hash", "KEY", "value#hash"},
+		{"KEY=#comment", "KEY", ""},
+		{"KEY='a # b'", "KEY", "a # b"},
+		{`KEY='a\nb'`, "KEY", `a\nb`},
+		{`KEY="it's"`, "KEY", "it's"},
+		{`KEY="a \"b\" \$c \\d\ne"`, "KEY", "a \"b\" $c \\d\ne"},
+		{"app.key_1=value", "app.key_1", "value"},
+		{"# KEY=value", "", ""},
+		{"   ", "", ""},
+	}
+
+	for _, test := range tests {
+		file, err := dotenv.Parse(strings.NewReader(test.line))
+		if err != nil {
+			t.Fatalf("Parse(%q) = %v", test.line, err)
+		}
+
+		if len(file.Lines) != 1 {
+			t.Fatalf("Parse(%q) = %d lines", test.line, len(file.Lines))
+		}
+
+		line := file.Lines[0]
+		if line.Key != test.key || line.Value != test.value {
+			t.Errorf("Parse(%q) = %q=%q, want %q=%q", test.line, line.Key, line.Value, test.key, test.value)
+		}
+	}
+
+	for _, invalid := range []string{"KEY", "1KEY=value", `KEY="value`, "KEY='value"} {
+		if _, err := dotenv.Parse(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Parse(%q) succeeded", invalid)
+		}
+	}
+}
+
+// TestQuoteRoundTrip checks that quoted values are parsed back unchanged.
+func TestQuoteRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	values := []string{
+		"",
+		"plain",
+		"a+b/c=d@e,f:g.h-i_j",
+		"with space",
+		"# not a comment",
+		"value #hash",
+		"it's",
+		`"double"`,
+		`it's "both"`,
+		`back\slash`,
+		`it's \n not a newline`,
+		"it's $HOME",
+		"multi\nline",
+		"it's\nmulti\\nline",
+		"trailing space ",
+	}
+
+	for _, value := range values {
+		line := "KEY=" + dotenv.Quote(value)
+
+		file, err := dotenv.Parse(strings.NewReader(line))
+		if err != nil {
+			t.Fatalf("Parse(%q) = %v", line, err)
+		}
+
+		if len(file.Lines) != 1 || file.Lines[0].Value != value {
+			t.Errorf("Quote(%q) = %q, parsed as %+v", value, line, file.Lines)
+		}
+	}
+}
+
+// TestSet replaces values while preserving comments, blank lines, prefixes and inline comments.
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	const content = "# database\n\nexport USER=admin\nPASSWORD=old # rotate monthly\n"
+
+	file, err := dotenv.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Lookup("MISSING") != nil {
+		t.Error("Lookup(MISSING) != nil")
+	}
+
+	file.Lookup("USER").Set("root")
+	file.Lookup("PASSWORD").Set("n3w p4ss")
+
+	var output strings.Builder
+
+	if err := file.Write(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "# database\n\nexport USER=root\nPASSWORD='n3w p4ss' # rotate monthly\n"
+
+	if output.String() != want {
+		t.Errorf("Write() = %q, want %q", output.String(), want)
+	}
+
+	reparsed, err := dotenv.Parse(strings.NewReader(output.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := reparsed.Lookup("PASSWORD").Value; value != "n3w p4ss" {
+		t.Errorf("PASSWORD = %q, want %q", value, "n3w p4ss")
+	}
+}
@@ -0,0 +1,103 @@
+// Package fingerprint provides short identifiers of keys, to confirm the right key is in use
+// without exposing it.
+//
+// Supported algorithms:
+//   - sha256: SHA-256 truncated to 16 bytes, in hex, of a symmetric key or the PKIX encoding of a public key
+//   - ssh:    OpenSSH "SHA256:" fingerprint of an RSA, ECDSA or Ed25519 public key, as shown by ssh-keygen -l
+//   - jwk:    RFC 7638 JWK thumbprint of a symmetric or public key
+//
+// Private keys are identified by their public key, so both have the same fingerprint.
+//
+// Example usage:
+//
+//	id, err := fingerprint.Of(key, fingerprint.SHA256)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package fingerprint
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/idelchi/gogen/pkg/jwk"
+	"github.com/idelchi/gogen/pkg/key"
+)
+
+// Algorithm is a fingerprint algorithm.
+type Algorithm string
+
+const (
+	// SHA256 is the truncated SHA-256 digest in hex.
+	SHA256 Algorithm = "sha256"
+	// SSH is the OpenSSH SHA-256 fingerprint.
+	SSH Algorithm = "ssh"
+	// JWK is the RFC 7638 JWK thumbprint.
+	JWK Algorithm = "jwk"
+)
+
+// size is the number of bytes of the truncated SHA-256 digest.
+const size = 16
+
+// Algorithms returns the names of the supported algorithms.
+func Algorithms() []string {
+	return []string{string(SHA256), string(SSH), string(JWK)}
+}
+
+// Of returns the fingerprint of a symmetric key (key.Key or []byte), or of a public or private key.
+func Of(value any, algorithm Algorithm) (string, error) {
+	if private, ok := value.(interface{ Public() crypto.PublicKey }); ok {
+		value = private.Public()
+	}
+
+	switch algorithm {
+	case SHA256:
+		return truncated(value)
+	case SSH:
+		public, err := ssh.NewPublicKey(value)
+		if err != nil {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return "", fmt.Errorf("ssh fingerprints require an RSA, ECDSA or Ed25519 key, got %T", value)
+		}
+
+		return ssh.FingerprintSHA256(public), nil
+	case JWK:
+		encoded, err := jwk.New(value)
+		if err != nil {
+			return "", err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		return encoded.Thumbprint(), nil
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("unsupported fingerprint algorithm %q, supported: %v", algorithm, Algorithms())
+	}
+}
+
+// truncated returns the truncated SHA-256 digest of a symmetric key or the PKIX encoding of a public key.
+func truncated(value any) (string, error) {
+	var encoded []byte
+
+	switch typed := value.(type) {
+	case key.Key:
+		encoded = typed
+	case []byte:
+		encoded = typed
+	default:
+		var err error
+
+		encoded, err = x509.MarshalPKIXPublicKey(value)
+		if err != nil {
+			return "", fmt.Errorf("encoding public key: %w", err)
+		}
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:size]), nil
+}
@@ -0,0 +1,380 @@
+// Package jwk provides JSON Web Keys (RFC 7517) of symmetric, public and private keys,
+// and their thumbprints (RFC 7638).
+//
+// Example usage:
+//
+//	jwk, err := jwk.New(publicKey)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fmt.Println(jwk.Thumbprint())
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/idelchi/gogen/pkg/key"
+)
+
+// JWK is a JSON Web Key, with its members base64url-encoded.
+type JWK struct {
+	// Kty is the key type (oct, RSA, EC, OKP)
+	Kty string `json:"kty"`
+
+	// Crv is the curve of EC and OKP keys
+	Crv string `json:"crv,omitempty"`
+
+	// X is the x-coordinate of EC keys, or the public key of OKP keys
+	X string `json:"x,omitempty"`
+
+	// Y is the y-coordinate of EC keys
+	Y string `json:"y,omitempty"`
+
+	// N is the modulus of RSA keys
+	N string `json:"n,omitempty"`
+
+	// E is the public exponent of RSA keys
+	E string `json:"e,omitempty"`
+
+	// D is the private exponent of RSA keys, or the private key of EC and OKP keys
+	D string `json:"d,omitempty"`
+
+	// P is the first prime factor of RSA keys
+	P string `json:"p,omitempty"`
+
+	// Q is the second prime factor of RSA keys
+	Q string `json:"q,omitempty"`
+
+	// DP is the first factor CRT exponent of RSA keys
+	DP string `json:"dp,omitempty"`
+
+	// DQ is the second factor CRT exponent of RSA keys
+	DQ string `json:"dq,omitempty"`
+
+	// QI is the first CRT coefficient of RSA keys
+	QI string `json:"qi,omitempty"`
+
+	// K is the value of symmetric keys
+	K string `json:"k,omitempty"`
+}
+
+// New returns the JWK of a symmetric key (key.Key or []byte) or a public key.
+// Private keys are represented by their public key, see NewPrivate to include the private members.
+func New(value any) (JWK, error) {
+	if private, ok := value.(interface{ Public() crypto.PublicKey }); ok {
+		value = private.Public()
+	}
+
+	switch typed := value.(type) {
+	case key.Key:
+		return JWK{Kty: "oct", K: encode(typed)}, nil
+	case []byte:
+		return JWK{Kty: "oct", K: encode(typed)}, nil
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: encode(typed.N.Bytes()), E: encode(big.NewInt(int64(typed.E)).Bytes())}, nil
+	case *ecdsa.PublicKey:
+		public, err := typed.ECDH()
+		if err != nil {
+			return JWK{}, fmt.Errorf("converting public key: %w", err)
+		}
+
+		return New(public)
+	case *ecdh.PublicKey:
+		return fromECDH(typed)
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: encode(typed)}, nil
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return JWK{}, fmt.Errorf("unsupported key type %T", value)
+	}
+}
+
+// NewPrivate returns the JWK of a private key, including its private members.
+func NewPrivate(private crypto.PrivateKey) (JWK, error) {
+	signer, ok := private.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return JWK{}, fmt.Errorf("unsupported private key type %T", private)
+	}
+
+	jwk, err := New(signer.Public())
+	if err != nil {
+		return JWK{}, err
+	}
+
+	switch typed := private.(type) {
+	case *rsa.PrivateKey:
+		typed.Precompute()
+
+		jwk.D = encode(typed.D.Bytes())
+		jwk.P = encode(typed.Primes[0].Bytes())
+		jwk.Q = encode(typed.Primes[1].Bytes())
+		jwk.DP = encode(typed.Precomputed.Dp.Bytes())
+		jwk.DQ = encode(typed.Precomputed.Dq.Bytes())
+		jwk.QI = encode(typed.Precomputed.Qinv.Bytes())
+	case *ecdsa.PrivateKey:
+		scalar, err := typed.Bytes()
+		if err != nil {
+			return JWK{}, fmt.Errorf("encoding private key: %w", err)
+		}
+
+		jwk.D = encode(scalar)
+	case *ecdh.PrivateKey:
+		jwk.D = encode(typed.Bytes())
+	case ed25519.PrivateKey:
+		jwk.D = encode(typed.Seed())
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return JWK{}, fmt.Errorf("unsupported private key type %T", private)
+	}
+
+	return jwk, nil
+}
+
+// Parse parses a JWK from its JSON serialization.
+func Parse(data []byte) (JWK, error) {
+	var jwk JWK
+
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return JWK{}, fmt.Errorf("parsing JWK: %w", err)
+	}
+
+	if jwk.Kty == "" {
+		return JWK{}, errors.New("parsing JWK: missing kty") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	return jwk, nil
+}
+
+// Key returns the key of the JWK: key.Key for symmetric keys, and otherwise
+// the private key if the JWK has private members, or else the public key.
+func (j JWK) Key() (any, error) {
+	switch j.Kty {
+	case "oct":
+		value, err := decode(j.K, "k")
+		if err != nil {
+			return nil, err
+		}
+
+		return key.Key(value), nil
+	case "RSA":
+		return j.rsa()
+	case "EC":
+		return j.ec()
+	case "OKP":
+		return j.okp()
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+	}
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the JWK: the base64url-encoded SHA-256 digest
+// of its required public members, serialized in lexicographic order without whitespace.
+func (j JWK) Thumbprint() string {
+	var required map[string]string
+
+	switch j.Kty {
+	case "oct":
+		required = map[string]string{"k": j.K}
+	case "RSA":
+		required = map[string]string{"e": j.E, "n": j.N}
+	case "EC":
+		required = map[string]string{"crv": j.Crv, "x": j.X, "y": j.Y}
+	default:
+		required = map[string]string{"crv": j.Crv, "x": j.X}
+	}
+
+	required["kty"] = j.Kty
+
+	// Maps are marshalled with sorted keys, and base64url values need no escaping.
+	serialized, _ := json.Marshal(required) //nolint:errchkjson	// Marshalling strings cannot fail.
+	sum := sha256.Sum256(serialized)
+
+	return encode(sum[:])
+}
+
+// rsa returns the RSA key of the JWK.
+func (j JWK) rsa() (any, error) {
+	values, err := decodeAll(map[string]string{"n": j.N, "e": j.E})
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(values["e"])
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(values["n"]), E: int(exponent.Int64())}
+
+	if j.D == "" {
+		return public, nil
+	}
+
+	values, err = decodeAll(map[string]string{"d": j.D, "p": j.P, "q": j.Q})
+	if err != nil {
+		return nil, err
+	}
+
+	private := &rsa.PrivateKey{
+		PublicKey: *public,
+		D:         new(big.Int).SetBytes(values["d"]),
+		Primes:    []*big.Int{new(big.Int).SetBytes(values["p"]), new(big.Int).SetBytes(values["q"])},
+	}
+
+	private.Precompute()
+
+	if err := private.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid RSA private key: %w", err)
+	}
+
+	return private, nil
+}
+
+// ec returns the ECDSA key of the JWK.
+func (j JWK) ec() (any, error) {
+	var curve elliptic.Curve
+
+	for _, named := range curves {
+		if named.name == j.Crv {
+			curve = named.elliptic
+		}
+	}
+
+	if curve == nil {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+	}
+
+	if j.D != "" {
+		scalar, err := decode(j.D, "d")
+		if err != nil {
+			return nil, err
+		}
+
+		private, err := ecdsa.ParseRawPrivateKey(curve, scalar)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC private key: %w", err)
+		}
+
+		return private, nil
+	}
+
+	values, err := decodeAll(map[string]string{"x": j.X, "y": j.Y})
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, values["x"]...), values["y"]...))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EC public key: %w", err)
+	}
+
+	return public, nil
+}
+
+// okp returns the Ed25519 or X25519 key of the JWK.
+func (j JWK) okp() (any, error) {
+	member, name := j.X, "x"
+	if j.D != "" {
+		member, name = j.D, "d"
+	}
+
+	value, err := decode(member, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case j.Crv == "Ed25519" && j.D != "" && len(value) == ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(value), nil
+	case j.Crv == "Ed25519" && j.D == "" && len(value) == ed25519.PublicKeySize:
+		return ed25519.PublicKey(value), nil
+	case j.Crv == "X25519" && j.D != "":
+		return ecdh.X25519().NewPrivateKey(value) //nolint:wrapcheck	// Error does not need additional wrapping.
+	case j.Crv == "X25519":
+		return ecdh.X25519().NewPublicKey(value) //nolint:wrapcheck	// Error does not need additional wrapping.
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("invalid OKP key with curve %q", j.Crv)
+	}
+}
+
+// curves maps the ECDH curves to their JWK names, elliptic curves and coordinate sizes.
+//
+//nolint:gochecknoglobals,mnd	// Constant lookup table.
+var curves = map[ecdh.Curve]struct {
+	name     string
+	elliptic elliptic.Curve
+	size     int
+}{
+	ecdh.P256(): {"P-256", elliptic.P256(), 32},
+	ecdh.P384(): {"P-384", elliptic.P384(), 48},
+	ecdh.P521(): {"P-521", elliptic.P521(), 66},
+}
+
+// fromECDH returns the JWK of an ECDH public key.
+func fromECDH(public *ecdh.PublicKey) (JWK, error) {
+	if public.Curve() == ecdh.X25519() {
+		return JWK{Kty: "OKP", Crv: "X25519", X: encode(public.Bytes())}, nil
+	}
+
+	curve, ok := curves[public.Curve()]
+	if !ok {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return JWK{}, fmt.Errorf("unsupported curve %v", public.Curve())
+	}
+
+	// The uncompressed point is 0x04 followed by the x- and y-coordinates.
+	point := public.Bytes()[1:]
+
+	return JWK{Kty: "EC", Crv: curve.name, X: encode(point[:curve.size]), Y: encode(point[curve.size:])}, nil
+}
+
+// encode returns the unpadded base64url encoding of the data.
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decode decodes the base64url-encoded member with the given name.
+func decode(value, name string) ([]byte, error) {
+	if value == "" {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("missing JWK member %q", name)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("decoding JWK member %q: %w", name, err)
+	}
+
+	return decoded, nil
+}
+
+// decodeAll decodes the base64url-encoded members by name.
+func decodeAll(members map[string]string) (map[string][]byte, error) {
+	decoded := make(map[string][]byte, len(members))
+
+	for name, value := range members {
+		var err error
+
+		if decoded[name], err = decode(value, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,134 @@
+// Package kdf provides derivation of keys from master keys with HKDF,
+// and from passphrases with Argon2id or scrypt.
+//
+// Derivation is deterministic: the same inputs always yield the same key,
+// so per-service subkeys can be derived from a single master key instead of being stored.
+//
+// Example usage:
+//
+//	subkey, err := kdf.HKDF(master, nil, []byte("database"), 32, kdf.SHA256)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package kdf
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/idelchi/gogen/pkg/argon"
+	"github.com/idelchi/gogen/pkg/key"
+)
+
+// Hash is the hash function of HKDF.
+type Hash string
+
+const (
+	// SHA256 is HKDF-SHA256.
+	SHA256 Hash = "sha256"
+	// SHA512 is HKDF-SHA512.
+	SHA512 Hash = "sha512"
+)
+
+// hash returns the constructor of the hash function.
+func (h Hash) hash() (func() hash.Hash, error) {
+	switch h {
+	case SHA256:
+		return sha256.New, nil
+	case SHA512:
+		return sha512.New, nil
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported hash %q", h)
+	}
+}
+
+// HKDF derives a key of the given length from the master key with HKDF (RFC 5869).
+// The info binds the key to its purpose, e.g. the name of a service; the salt is optional.
+func HKDF(master key.Key, salt, info []byte, length int, hash Hash) (key.Key, error) {
+	constructor, err := hash.hash()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(master) == 0 {
+		return nil, errors.New("master key must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	if maximum := 255 * constructor().Size(); length < 1 || length > maximum {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("length must be between 1 and %d bytes for hkdf-%s, got %d", maximum, hash, length)
+	}
+
+	derived := make(key.Key, length)
+	if _, err := io.ReadFull(hkdf.New(constructor, master, salt, info), derived); err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+
+	return derived, nil
+}
+
+// Argon2id derives a key of the given length from the passphrase and salt with Argon2id.
+func Argon2id(passphrase, salt []byte, length int, params argon.Params) (key.Key, error) {
+	if err := validatePassphrase(passphrase, salt, length); err != nil {
+		return nil, err
+	}
+
+	return argon.Key(passphrase, salt, uint32(length), params), nil //nolint:gosec	// Length is validated.
+}
+
+// ScryptParams are the cost parameters of scrypt.
+type ScryptParams struct {
+	// LogN is the base-2 logarithm of the CPU/memory cost N
+	LogN int
+
+	// R is the block size
+	R int
+
+	// P is the parallelization
+	P int
+}
+
+// DefaultScryptParams are the parameters recommended for interactive logins (N=2^15, r=8, p=1).
+//
+//nolint:gochecknoglobals,mnd	// Default parameters.
+var DefaultScryptParams = ScryptParams{LogN: 15, R: 8, P: 1}
+
+// Scrypt derives a key of the given length from the passphrase and salt with scrypt.
+func Scrypt(passphrase, salt []byte, length int, params ScryptParams) (key.Key, error) {
+	if err := validatePassphrase(passphrase, salt, length); err != nil {
+		return nil, err
+	}
+
+	derived, err := scrypt.Key(passphrase, salt, 1<<params.LogN, params.R, params.P, length)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+
+	return derived, nil
+}
+
+// validatePassphrase checks the inputs of passphrase-based derivation.
+// The salt is required, as it is the only input besides the passphrase that makes keys unique.
+func validatePassphrase(passphrase, salt []byte, length int) error {
+	const maxLength = 1024
+
+	switch {
+	case len(passphrase) == 0:
+		return errors.New("passphrase must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	case len(salt) == 0:
+		return errors.New("salt must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	case length < 1 || length > maxLength:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return fmt.Errorf("length must be between 1 and %d bytes, got %d", maxLength, length)
+	}
+
+	return nil
+}
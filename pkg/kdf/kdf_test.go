@@ -0,0 +1,165 @@
+package kdf_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/argon"
+	"github.com/idelchi/gogen/pkg/kdf"
+)
+
+// decode decodes a hex string of a test vector.
+func decode(t *testing.T, encoded string) []byte {
+	t.Helper()
+
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded
+}
+
+// TestHKDF checks the SHA-256 test cases 1 and 3 of RFC 5869.
+func TestHKDF(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		salt string
+		info string
+		want string
+	}{
+		{
+			name: "basic",
+			salt: "000102030405060708090a0b0c",
+			info: "f0f1f2f3f4f5f6f7f8f9",
+			want: "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{
+			name: "zero-length salt and info",
+			want: "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	}
+
+	master := bytes.Repeat([]byte{0x0b}, 22)
+
+	for _, test := range tests {
+		derived, err := kdf.HKDF(master, decode(t, test.salt), decode(t, test.info), 42, kdf.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := derived.AsHex(); got != test.want {
+			t.Errorf("%s: HKDF = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+// TestHKDFErrors rejects empty master keys, unsupported hashes and lengths HKDF cannot produce.
+func TestHKDFErrors(t *testing.T) {
+	t.Parallel()
+
+	master := []byte("master")
+
+	if _, err := kdf.HKDF(nil, nil, nil, 32, kdf.SHA256); err == nil {
+		t.Error("empty master key succeeded")
+	}
+
+	if _, err := kdf.HKDF(master, nil, nil, 32, "md5"); err == nil {
+		t.Error("unsupported hash succeeded")
+	}
+
+	for _, length := range []int{0, 255*32 + 1} {
+		if _, err := kdf.HKDF(master, nil, nil, length, kdf.SHA256); err == nil {
+			t.Errorf("length %d succeeded", length)
+		}
+	}
+
+	if _, err := kdf.HKDF(master, nil, nil, 255*64, kdf.SHA512); err != nil {
+		t.Errorf("maximum length of hkdf-sha512: %v", err)
+	}
+}
+
+// TestScrypt checks the test vectors of RFC 7914, section 12.
+func TestScrypt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		passphrase string
+		salt       string
+		params     kdf.ScryptParams
+		want       string
+	}{
+		{
+			passphrase: "password",
+			salt:       "NaCl",
+			params:     kdf.ScryptParams{LogN: 10, R: 8, P: 16},
+			want: "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b373162" +
+				"2eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640",
+		},
+		{
+			passphrase: "pleaseletmein",
+			salt:       "SodiumChloride",
+			params:     kdf.ScryptParams{LogN: 14, R: 8, P: 1},
+			want: "7023bdcb3afd7348461c06cd81fd38ebfda8fbba904f8e3ea9b543f6545da1f2" +
+				"d5432955613f0fcf62d49705242a9af9e61e85dc0d651e40dfcf017b45575887",
+		},
+	}
+
+	for _, test := range tests {
+		derived, err := kdf.Scrypt([]byte(test.passphrase), []byte(test.salt), 64, test.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := derived.AsHex(); got != test.want {
+			t.Errorf("Scrypt(%s) = %s, want %s", test.passphrase, got, test.want)
+		}
+	}
+}
+
+// TestArgon2id checks the Argon2id test vector of the reference implementation (t=2, m=2^16, p=1).
+func TestArgon2id(t *testing.T) {
+	t.Parallel()
+
+	const want = "09316115d5cf24ed5a15a31a3ba326e5cf32edc24702987c02b6566f61913cf7"
+
+	params := argon.Params{Iterations: 2, Memory: 1 << 16, Parallelism: 1}
+
+	derived, err := kdf.Argon2id([]byte("password"), []byte("somesalt"), 32, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := derived.AsHex(); got != want {
+		t.Errorf("Argon2id = %s, want %s", got, want)
+	}
+}
+
+// TestPassphraseErrors rejects empty passphrases and salts, and unreasonable lengths.
+func TestPassphraseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		passphrase string
+		salt       string
+		length     int
+	}{
+		"empty passphrase": {"", "salt", 32},
+		"empty salt":       {"passphrase", "", 32},
+		"zero length":      {"passphrase", "salt", 0},
+		"excessive length": {"passphrase", "salt", 1025},
+	}
+
+	for name, test := range tests {
+		if _, err := kdf.Scrypt([]byte(test.passphrase), []byte(test.salt), test.length, kdf.DefaultScryptParams); err == nil {
+			t.Errorf("scrypt: %s succeeded", name)
+		}
+
+		if _, err := kdf.Argon2id([]byte(test.passphrase), []byte(test.salt), test.length, argon.DefaultParams); err == nil {
+			t.Errorf("argon2id: %s succeeded", name)
+		}
+	}
+}
@@ -2,7 +2,8 @@
 //
 // The package supports:
 //   - Generating cryptographically secure random keys of arbitrary length
-//   - Converting between raw bytes and hexadecimal string representations
+//   - Converting between raw bytes and hexadecimal, base64 or base32 string representations
+//   - Backing up keys of 16 to 32 bytes as BIP39 mnemonics
 //
 // Example usage:
 //
@@ -24,9 +25,13 @@ package key
 
 import (
 	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"strings"
+
+	"github.com/idelchi/gogen/pkg/mnemonic"
 )
 
 // Key represents a cryptographic key as a byte slice.
@@ -62,7 +67,103 @@ func FromHex(hexKey string) (Key, error) {
 	return key, nil
 }
 
+// FromBase64 creates a Key by decoding a base64 string.
+// It trims any whitespace and accepts the standard and URL-safe alphabets, with or without padding.
+// Returns an error if the base64 string is invalid.
+func FromBase64(base64Key string) (Key, error) {
+	trimmed := strings.TrimRight(strings.TrimSpace(base64Key), "=")
+
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(trimmed, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+
+	key, err := encoding.DecodeString(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 key: %w", err)
+	}
+
+	return key, nil
+}
+
 // AsHex returns the Key as a lowercase hexadecimal string.
 func (k Key) AsHex() string {
 	return hex.EncodeToString(k)
 }
+
+// AsBase64 returns the Key as a padded standard base64 string.
+func (k Key) AsBase64() string {
+	return base64.StdEncoding.EncodeToString(k)
+}
+
+// FromMnemonic creates a Key by decoding a BIP39 mnemonic of 12 to 24 words.
+// Returns an error if a word is unknown or the checksum does not match.
+func FromMnemonic(phrase string) (Key, error) {
+	key, err := mnemonic.ToEntropy(phrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic key: %w", err)
+	}
+
+	return key, nil
+}
+
+// AsMnemonic returns the Key as a BIP39 mnemonic of 12 to 24 words.
+// Only keys of 16, 20, 24, 28 or 32 bytes can be encoded.
+func (k Key) AsMnemonic() (string, error) {
+	phrase, err := mnemonic.FromEntropy(k)
+	if err != nil {
+		return "", fmt.Errorf("encoding mnemonic: %w", err)
+	}
+
+	return phrase, nil
+}
+
+// Encodings returns the names of the supported string encodings of keys.
+func Encodings() []string {
+	return []string{"hex", "base64", "base64url", "base32", "mnemonic"}
+}
+
+// Encode returns the Key in the named encoding:
+// hex (lowercase), base64 (standard, padded), base64url (URL-safe, unpadded), base32 (standard, padded)
+// or mnemonic (BIP39 English words with checksum, for keys of 16, 20, 24, 28 or 32 bytes).
+func (k Key) Encode(encoding string) (string, error) {
+	switch encoding {
+	case "hex":
+		return k.AsHex(), nil
+	case "base64":
+		return k.AsBase64(), nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(k), nil
+	case "base32":
+		return base32.StdEncoding.EncodeToString(k), nil
+	case "mnemonic":
+		return k.AsMnemonic()
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("unsupported encoding %q, supported: %v", encoding, Encodings())
+	}
+}
+
+// Decode creates a Key by decoding a string in the named encoding, see Encode.
+// Base64 decoding accepts both alphabets, with or without padding.
+func Decode(encoded, encoding string) (Key, error) {
+	switch encoding {
+	case "hex":
+		return FromHex(encoded)
+	case "base64", "base64url":
+		return FromBase64(encoded)
+	case "base32":
+		key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(
+			strings.TrimRight(strings.ToUpper(strings.TrimSpace(encoded)), "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base32 key: %w", err)
+		}
+
+		return key, nil
+	case "mnemonic":
+		return FromMnemonic(encoded)
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported encoding %q, supported: %v", encoding, Encodings())
+	}
+}
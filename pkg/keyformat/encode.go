@@ -0,0 +1,214 @@
+package keyformat
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/idelchi/gogen/pkg/jwk"
+)
+
+// Format is a key serialization format.
+type Format string
+
+const (
+	// PKCS1 is PKCS#1 (RSA PRIVATE KEY, RSA PUBLIC KEY), for RSA keys only.
+	PKCS1 Format = "pkcs1"
+	// PKCS8 is PKCS#8 (PRIVATE KEY, ENCRYPTED PRIVATE KEY), for private keys.
+	PKCS8 Format = "pkcs8"
+	// SEC1 is SEC 1 (EC PRIVATE KEY), for ECDSA private keys only.
+	SEC1 Format = "sec1"
+	// PKIX is the X.509 SubjectPublicKeyInfo (PUBLIC KEY), for public keys.
+	PKIX Format = "pkix"
+	// OpenSSH is the OpenSSH private key format, and the authorized_keys format for public keys.
+	OpenSSH Format = "openssh"
+	// JWK is the JSON Web Key format.
+	JWK Format = "jwk"
+)
+
+// Formats returns the names of the supported formats.
+func Formats() []string {
+	return []string{string(PKCS1), string(PKCS8), string(SEC1), string(PKIX), string(OpenSSH), string(JWK)}
+}
+
+// Options configure the serialization of keys.
+type Options struct {
+	// Encrypt encrypts private keys in the PKCS#8 and OpenSSH formats with the passphrase
+	Encrypt bool
+
+	// Passphrase is the passphrase to encrypt with, which must not be empty when encrypting
+	Passphrase []byte
+
+	// Comment is the comment of OpenSSH keys
+	Comment string
+}
+
+// Public returns the public key of a private key, or the key itself if it is not a private key.
+func Public(key any) any {
+	if private, ok := key.(interface{ Public() crypto.PublicKey }); ok {
+		return private.Public()
+	}
+
+	return key
+}
+
+// IsPrivate reports whether the key is a private key.
+func IsPrivate(key any) bool {
+	_, ok := key.(interface{ Public() crypto.PublicKey })
+
+	return ok
+}
+
+// Encode serializes a private or public key in the format, as PEM except for OpenSSH public keys and JWKs.
+// As PKIX only holds public keys, the public key of private keys is written.
+// Keys to encrypt are never written in plain text: encrypting fails for other keys and formats,
+// and for empty passphrases.
+func Encode(key any, format Format, options Options) ([]byte, error) {
+	switch {
+	case !options.Encrypt && len(options.Passphrase) > 0:
+		return nil, errors.New("passphrase given without encryption") //nolint:err113 // Occasional dynamic errors are fine.
+	case options.Encrypt && (!IsPrivate(key) || (format != PKCS8 && format != OpenSSH)):
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("only private keys in the %s and %s formats can be encrypted", PKCS8, OpenSSH)
+	case options.Encrypt && len(options.Passphrase) == 0:
+		return nil, errors.New("passphrase must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	switch format {
+	case PKCS1:
+		return encodePKCS1(key)
+	case PKCS8:
+		return encodePKCS8(key, options)
+	case SEC1:
+		private, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, unsupported(key, format)
+		}
+
+		der, err := x509.MarshalECPrivateKey(private)
+
+		return encodePEM("EC PRIVATE KEY", der, err)
+	case PKIX:
+		der, err := x509.MarshalPKIXPublicKey(Public(key))
+
+		return encodePEM("PUBLIC KEY", der, err)
+	case OpenSSH:
+		return encodeOpenSSH(key, options)
+	case JWK:
+		return encodeJWK(key)
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported format %q, supported: %v", format, Formats())
+	}
+}
+
+// encodePKCS1 serializes an RSA private or public key as PKCS#1.
+func encodePKCS1(key any) ([]byte, error) {
+	switch typed := key.(type) {
+	case *rsa.PrivateKey:
+		return encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(typed), nil)
+	case *rsa.PublicKey:
+		return encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(typed), nil)
+	default:
+		return nil, unsupported(key, PKCS1)
+	}
+}
+
+// encodePKCS8 serializes a private key as PKCS#8, encrypted if requested by the options.
+func encodePKCS8(key any, options Options) ([]byte, error) {
+	if !IsPrivate(key) {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("%s holds private keys, use %s for public keys", PKCS8, PKIX)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil || !options.Encrypt {
+		return encodePEM("PRIVATE KEY", der, err)
+	}
+
+	encrypted, err := encryptPKCS8(der, options.Passphrase)
+
+	return encodePEM("ENCRYPTED PRIVATE KEY", encrypted, err)
+}
+
+// encodeOpenSSH serializes a private key in the OpenSSH format, or a public key in the authorized_keys format.
+func encodeOpenSSH(key any, options Options) ([]byte, error) {
+	if !IsPrivate(key) {
+		public, err := ssh.NewPublicKey(key)
+		if err != nil {
+			return nil, unsupported(key, OpenSSH)
+		}
+
+		line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(public)), "\n")
+		if options.Comment != "" {
+			line += " " + options.Comment
+		}
+
+		return []byte(line + "\n"), nil
+	}
+
+	var (
+		block *pem.Block
+		err   error
+	)
+
+	if options.Encrypt {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, options.Comment, options.Passphrase)
+	} else {
+		block, err = ssh.MarshalPrivateKey(key, options.Comment)
+	}
+
+	if err != nil {
+		return nil, unsupported(key, OpenSSH)
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// encodeJWK serializes a private or public key as an indented JWK.
+func encodeJWK(key any) ([]byte, error) {
+	var (
+		encoded jwk.JWK
+		err     error
+	)
+
+	if IsPrivate(key) {
+		encoded, err = jwk.NewPrivate(key)
+	} else {
+		encoded, err = jwk.New(key)
+	}
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	serialized, err := json.MarshalIndent(encoded, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding JWK: %w", err)
+	}
+
+	return append(serialized, '\n'), nil
+}
+
+// encodePEM encodes DER as a PEM block of the type, passing through errors of the DER encoding.
+func encodePEM(blockType string, der []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", blockType, err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), nil
+}
+
+// unsupported returns an error for a key that cannot be serialized in the format.
+func unsupported(key any, format Format) error {
+	//nolint:err113 // Occasional dynamic errors are fine.
+	return fmt.Errorf("%T cannot be written as %s", key, format)
+}
@@ -0,0 +1,190 @@
+package keyformat_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/keyformat"
+)
+
+// passphrase returns a passphrase function for the given passphrase.
+func passphrase(secret string) keyformat.Passphrase {
+	return func() ([]byte, error) { return []byte(secret), nil }
+}
+
+// read returns the content of a file in testdata.
+func read(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+// pkcs8 returns the DER-encoded PKCS#8 form of a private key, to compare keys.
+func pkcs8(t *testing.T, key any) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return der
+}
+
+// TestDecryptOpenSSLPKCS8 decrypts PBES2 keys encrypted by openssl with every supported PRF and AES key size.
+func TestDecryptOpenSSLPKCS8(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encrypted string
+		plain     string
+	}{
+		{"ed25519-aes256-sha256.pem", "ed25519.pem"},
+		{"p256-aes128-sha1.pem", "p256.pem"},
+		{"p256-aes192-sha512.pem", "p256.pem"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.encrypted, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := keyformat.Parse(read(t, test.plain))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, format, err := keyformat.Decode(read(t, test.encrypted), passphrase("gogen"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if format != keyformat.PKCS8 {
+				t.Errorf("format = %q, want %q", format, keyformat.PKCS8)
+			}
+
+			if !bytes.Equal(pkcs8(t, got), pkcs8(t, want)) {
+				t.Error("decrypted key does not match the plain key")
+			}
+		})
+	}
+}
+
+// TestDecryptPassphraseErrors checks the errors for wrong and missing passphrases.
+func TestDecryptPassphraseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"ed25519-aes256-sha256.pem", "p256-aes128-sha1.pem", "openssh-ed25519.pem"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, _, err := keyformat.Decode(read(t, name), passphrase("wrong")); !errors.Is(err, keyformat.ErrIncorrectPassphrase) {
+				t.Errorf("wrong passphrase: error = %v, want %v", err, keyformat.ErrIncorrectPassphrase)
+			}
+
+			if _, _, err := keyformat.Decode(read(t, name), nil); !errors.Is(err, keyformat.ErrPassphrase) {
+				t.Errorf("missing passphrase: error = %v, want %v", err, keyformat.ErrPassphrase)
+			}
+		})
+	}
+}
+
+// TestDecryptOpenSSH decrypts a key encrypted by ssh-keygen and compares it to its public key.
+func TestDecryptOpenSSH(t *testing.T) {
+	t.Parallel()
+
+	private, format, err := keyformat.Decode(read(t, "openssh-ed25519.pem"), passphrase("gogen"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if format != keyformat.OpenSSH {
+		t.Errorf("format = %q, want %q", format, keyformat.OpenSSH)
+	}
+
+	public, err := keyformat.Parse(read(t, "openssh-ed25519.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !public.(ed25519.PublicKey).Equal(keyformat.Public(private)) {
+		t.Error("decrypted key does not match the public key")
+	}
+
+	// Without the passphrase, the public key stored in clear is returned.
+	stored, err := keyformat.Parse(read(t, "openssh-ed25519.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !public.(ed25519.PublicKey).Equal(stored) {
+		t.Error("parsed public key does not match the public key")
+	}
+}
+
+// TestEncryptedRoundTrip encrypts keys in the PKCS#8 and OpenSSH formats and decrypts them again.
+func TestEncryptedRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	_, ed, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ec, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []any{ed, ec} {
+		for _, format := range []keyformat.Format{keyformat.PKCS8, keyformat.OpenSSH} {
+			encoded, err := keyformat.Encode(key, format, keyformat.Options{Encrypt: true, Passphrase: []byte("gogen")})
+			if err != nil {
+				t.Fatalf("%s %T: %v", format, key, err)
+			}
+
+			decoded, decodedFormat, err := keyformat.Decode(encoded, passphrase("gogen"))
+			if err != nil {
+				t.Fatalf("%s %T: %v", format, key, err)
+			}
+
+			if decodedFormat != format {
+				t.Errorf("%s %T: format = %q", format, key, decodedFormat)
+			}
+
+			if !bytes.Equal(pkcs8(t, decoded), pkcs8(t, key)) {
+				t.Errorf("%s %T: decrypted key does not match", format, key)
+			}
+		}
+	}
+}
+
+// TestEncryptEmptyPassphrase never falls back to plain text when encrypting with an empty passphrase.
+func TestEncryptEmptyPassphrase(t *testing.T) {
+	t.Parallel()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []keyformat.Format{keyformat.PKCS8, keyformat.OpenSSH} {
+		for _, passphrase := range [][]byte{nil, {}} {
+			if encoded, err := keyformat.Encode(key, format, keyformat.Options{Encrypt: true, Passphrase: passphrase}); err == nil {
+				t.Errorf("%s: encrypting with an empty passphrase succeeded:\n%s", format, encoded)
+			}
+		}
+	}
+}
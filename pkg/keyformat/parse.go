@@ -0,0 +1,272 @@
+// Package keyformat parses and serializes asymmetric keys in their common formats:
+// PKCS#1, PKCS#8, SEC1, PKIX, OpenSSH and JWK.
+//
+// Keys are read from PEM blocks (including certificates), DER, OpenSSH public keys in authorized_keys format,
+// and JSON Web Keys, with the format detected automatically. Encrypted PKCS#8 (PBES2), OpenSSH
+// and legacy encrypted PEM keys are decrypted with a passphrase, and PKCS#8 and OpenSSH keys can be encrypted.
+//
+// Example usage:
+//
+//	parsed, format, err := keyformat.Decode(data, nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	encoded, err := keyformat.Encode(parsed, keyformat.JWK, keyformat.Options{})
+package keyformat
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/idelchi/gogen/pkg/jwk"
+)
+
+var (
+	// ErrFormat indicates data that is not a key in a supported format.
+	ErrFormat = errors.New("unsupported key format")
+
+	// ErrPassphrase indicates an encrypted key given without a passphrase.
+	ErrPassphrase = errors.New("key is encrypted, a passphrase is required")
+
+	// ErrIncorrectPassphrase indicates an encrypted key that cannot be decrypted with the passphrase.
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase")
+)
+
+// Passphrase returns the passphrase of an encrypted key.
+// It is only called for encrypted keys, e.g. to prompt for the passphrase only when needed.
+type Passphrase func() ([]byte, error)
+
+// IsKey reports whether the data looks like a PEM block, an OpenSSH public key or a JWK,
+// as opposed to an encoded symmetric key.
+func IsKey(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("-----BEGIN ")) || bytes.HasPrefix(trimmed, []byte("{")) {
+		return true
+	}
+
+	_, _, _, _, err := ssh.ParseAuthorizedKey(trimmed) //nolint:dogsled	// Only the error is needed.
+
+	return err == nil
+}
+
+// Parse parses a private or public key in any supported format, see Decode.
+//
+// Encrypted OpenSSH private keys cannot be parsed without their passphrase,
+// so their public key, which is stored in clear, is returned instead.
+func Parse(data []byte) (any, error) {
+	parsed, _, err := Decode(data, nil)
+
+	if missing := (*ssh.PassphraseMissingError)(nil); errors.As(err, &missing) && missing.PublicKey != nil {
+		return fromSSH(missing.PublicKey)
+	}
+
+	return parsed, err
+}
+
+// Decode parses a private or public key, detecting its format from the first PEM block of the data,
+// an OpenSSH public key, a JWK or DER. The passphrase is only requested for encrypted keys, and may be nil.
+//
+// Private keys are returned as *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey or *ecdh.PrivateKey,
+// public keys as *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or *ecdh.PublicKey.
+func Decode(data []byte, passphrase Passphrase) (any, Format, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		parsed, err := jwk.Parse(trimmed)
+		if err != nil {
+			return nil, "", err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		key, err := parsed.Key()
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing JWK: %w", err)
+		}
+
+		return key, JWK, nil
+	}
+
+	if block, _ := pem.Decode(trimmed); block != nil {
+		parsed, format, err := parseBlock(block, passphrase)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing %s: %w", block.Type, err)
+		}
+
+		return normalize(parsed), format, nil
+	}
+
+	if public, _, _, _, err := ssh.ParseAuthorizedKey(trimmed); err == nil {
+		parsed, err := fromSSH(public)
+
+		return parsed, OpenSSH, err
+	}
+
+	return parseDER(data)
+}
+
+// parseBlock parses the key of a PEM block according to its type.
+//
+//nolint:gocognit	// Flat switch over the PEM block types.
+func parseBlock(block *pem.Block, passphrase Passphrase) (any, Format, error) {
+	der := block.Bytes
+
+	//nolint:staticcheck	// Legacy encrypted PEM is insecure, but still needs to be read to be converted.
+	if x509.IsEncryptedPEMBlock(block) {
+		secret, err := request(passphrase)
+		if err != nil {
+			return nil, "", err
+		}
+
+		//nolint:staticcheck	// See above.
+		if der, err = x509.DecryptPEMBlock(block, secret); err != nil {
+			return nil, "", ErrIncorrectPassphrase
+		}
+	}
+
+	var (
+		parsed any
+		format Format
+		err    error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(der)
+		format = PKCS1
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(der)
+		format = PKCS1
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(der)
+		format = SEC1
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(der)
+		format = PKCS8
+	case "ENCRYPTED PRIVATE KEY":
+		var secret []byte
+
+		if secret, err = request(passphrase); err != nil {
+			return nil, "", err
+		}
+
+		if der, err = decryptPKCS8(der, secret); err != nil {
+			return nil, "", err
+		}
+
+		parsed, err = x509.ParsePKCS8PrivateKey(der)
+		format = PKCS8
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(der)
+		format = PKIX
+	case "CERTIFICATE":
+		var certificate *x509.Certificate
+
+		if certificate, err = x509.ParseCertificate(der); err == nil {
+			parsed = certificate.PublicKey
+		}
+
+		format = PKIX
+	case "OPENSSH PRIVATE KEY":
+		parsed, err = parseOpenSSH(block, passphrase)
+		format = OpenSSH
+	default:
+		return nil, "", fmt.Errorf("%w: PEM block type %q", ErrFormat, block.Type)
+	}
+
+	if err != nil {
+		return nil, "", err //nolint:wrapcheck	// Error is wrapped by the caller.
+	}
+
+	return parsed, format, nil
+}
+
+// parseOpenSSH parses an OpenSSH private key, requesting the passphrase if it is encrypted.
+func parseOpenSSH(block *pem.Block, passphrase Passphrase) (any, error) {
+	encoded := pem.EncodeToMemory(block)
+
+	parsed, err := ssh.ParseRawPrivateKey(encoded)
+
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		return parsed, err //nolint:wrapcheck	// Error is wrapped by the caller.
+	}
+
+	if passphrase == nil {
+		return nil, fmt.Errorf("%w: %w", ErrPassphrase, err)
+	}
+
+	secret, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err = ssh.ParseRawPrivateKeyWithPassphrase(encoded, secret)
+	if errors.Is(err, x509.IncorrectPasswordError) {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return parsed, err //nolint:wrapcheck	// Error is wrapped by the caller.
+}
+
+// parseDER parses a DER-encoded PKCS#8, PKCS#1, SEC1 or PKIX key.
+func parseDER(der []byte) (any, Format, error) {
+	if parsed, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return parsed, PKCS8, nil
+	}
+
+	if parsed, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return parsed, PKCS1, nil
+	}
+
+	if parsed, err := x509.ParseECPrivateKey(der); err == nil {
+		return parsed, SEC1, nil
+	}
+
+	if parsed, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return parsed, PKIX, nil
+	}
+
+	if parsed, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return parsed, PKCS1, nil
+	}
+
+	return nil, "", fmt.Errorf("%w: neither PEM, DER, an OpenSSH public key nor a JWK", ErrFormat)
+}
+
+// request calls the passphrase function, or returns ErrPassphrase if there is none.
+func request(passphrase Passphrase) ([]byte, error) {
+	if passphrase == nil {
+		return nil, ErrPassphrase
+	}
+
+	return passphrase()
+}
+
+// fromSSH returns the standard library key of an OpenSSH public key.
+func fromSSH(public ssh.PublicKey) (any, error) {
+	crypto, ok := public.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: OpenSSH key type %q", ErrFormat, public.Type())
+	}
+
+	return normalize(crypto.CryptoPublicKey()), nil
+}
+
+// normalize dereferences the Ed25519 key pointers returned by the OpenSSH parsers.
+func normalize(parsed any) any {
+	switch typed := parsed.(type) {
+	case *ed25519.PrivateKey:
+		return *typed
+	case *ed25519.PublicKey:
+		return *typed
+	default:
+		return parsed
+	}
+}
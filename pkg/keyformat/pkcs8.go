@@ -0,0 +1,221 @@
+package keyformat
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec	// HMAC-SHA1 is the default PRF of PBKDF2, only used to decrypt existing keys.
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"hash"
+)
+
+// Object identifiers of PKCS#5 v2 (RFC 8018) and the AES-CBC ciphers.
+//
+//nolint:gochecknoglobals,mnd	// Constant object identifiers.
+var (
+	oidPBES2      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// pbkdf2Iterations is the number of PBKDF2-HMAC-SHA256 iterations used to encrypt keys.
+const pbkdf2Iterations = 600000
+
+// encryptedPrivateKeyInfo is the ASN.1 structure of an encrypted PKCS#8 key (RFC 5958).
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params are the parameters of PBES2.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of PBKDF2, where a missing PRF means HMAC-SHA1.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// encryptPKCS8 encrypts a DER-encoded PKCS#8 key with PBES2, using PBKDF2-HMAC-SHA256 and AES-256-CBC,
+// as written by openssl pkcs8 -topk8 -v2 aes-256-cbc.
+func encryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, 16) //nolint:mnd	// 128-bit salt.
+	iv := make([]byte, aes.BlockSize)
+
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("generating iv: %w", err)
+	}
+
+	const keySize = 32
+
+	derived, err := pbkdf2.Key(sha256.New, string(passphrase), salt, pbkdf2Iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	// PKCS#7 padding always adds between 1 and a full block of padding bytes.
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	ciphertext := append(append([]byte{}, der...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	kdf, err := algorithm(oidPBKDF2, pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, err := algorithm(oidAES256CBC, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	pbes2, err := algorithm(oidPBES2, pbes2Params{KeyDerivationFunc: kdf, EncryptionScheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := asn1.Marshal(encryptedPrivateKeyInfo{Algorithm: pbes2, EncryptedData: ciphertext})
+	if err != nil {
+		return nil, fmt.Errorf("encoding encrypted key: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// decryptPKCS8 decrypts a PBES2-encrypted PKCS#8 key with PBKDF2 and AES-CBC, returning the DER-encoded key.
+func decryptPKCS8(encoded, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(encoded, &info); err != nil {
+		return nil, fmt.Errorf("parsing encrypted key: %w", err)
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("%w: encryption algorithm %v, only PBES2 is supported", ErrFormat, info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("parsing PBES2 parameters: %w", err)
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("%w: key derivation %v, only PBKDF2 is supported",
+			ErrFormat, params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("parsing PBKDF2 parameters: %w", err)
+	}
+
+	prf, err := pseudoRandomFunction(kdf.PRF.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	keySize, err := aesKeySize(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: invalid AES-CBC iv", ErrFormat)
+	}
+
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("%w: invalid encrypted data length", ErrFormat)
+	}
+
+	derived, err := pbkdf2.Key(prf, string(passphrase), kdf.Salt, kdf.IterationCount, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.EncryptedData)
+
+	// A wrong passphrase almost always results in invalid padding.
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize ||
+		!bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// pseudoRandomFunction returns the hash function of the PBKDF2 PRF, where an empty identifier means HMAC-SHA1.
+func pseudoRandomFunction(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0 || oid.Equal(oidHMACSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHMACSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHMACSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidHMACSHA512):
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: PBKDF2 PRF %v", ErrFormat, oid)
+	}
+}
+
+// aesKeySize returns the key size of the AES-CBC cipher.
+func aesKeySize(oid asn1.ObjectIdentifier) (int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, nil //nolint:mnd	// AES-128.
+	case oid.Equal(oidAES192CBC):
+		return 24, nil //nolint:mnd	// AES-192.
+	case oid.Equal(oidAES256CBC):
+		return 32, nil //nolint:mnd	// AES-256.
+	default:
+		return 0, fmt.Errorf("%w: cipher %v, only AES-CBC is supported", ErrFormat, oid)
+	}
+}
+
+// algorithm returns an algorithm identifier with the DER-encoded parameters.
+func algorithm(oid asn1.ObjectIdentifier, params any) (pkix.AlgorithmIdentifier, error) {
+	encoded, err := asn1.Marshal(params)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, fmt.Errorf("encoding parameters: %w", err)
+	}
+
+	return pkix.AlgorithmIdentifier{Algorithm: oid, Parameters: asn1.RawValue{FullBytes: encoded}}, nil
+}
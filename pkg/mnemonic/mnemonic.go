@@ -0,0 +1,231 @@
+// Package mnemonic encodes binary data as words of the BIP39 English wordlist,
+// which are easier to write down and read back than hexadecimal strings.
+//
+// FromEntropy and ToEntropy implement BIP39 mnemonics of 16 to 32 bytes of entropy with a checksum,
+// while Encode and Decode handle data of any length up to MaxLength.
+//
+// Every word carries 11 bits. When decoding, words are matched case-insensitively
+// and may be abbreviated to their first four letters, which are unique within the wordlist.
+//
+// Example usage:
+//
+//	phrase, err := mnemonic.Encode(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	restored, err := mnemonic.Decode(phrase)
+package mnemonic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The BIP39 English wordlist with 2048 words.
+// See https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt.
+//
+//go:embed wordlists/english.txt
+var english string
+
+//nolint:gochecknoglobals	// Wordlist and lookup table, parsed once from the embedded file.
+var (
+	wordlist = strings.Fields(english)
+	lookup   = indexWords(wordlist)
+)
+
+const (
+	// bitsPerWord is the number of bits encoded by a single word.
+	bitsPerWord = 11
+
+	// prefixLength is the number of letters identifying a word uniquely.
+	prefixLength = 4
+
+	// MaxLength is the maximum length of data encoded by Encode, bounded by the length word.
+	MaxLength = 1<<bitsPerWord - 1
+)
+
+var (
+	// ErrWord indicates a word that is not part of the wordlist.
+	ErrWord = errors.New("unknown word")
+
+	// ErrChecksum indicates a BIP39 mnemonic whose checksum does not match its entropy.
+	ErrChecksum = errors.New("invalid mnemonic checksum")
+)
+
+// FromEntropy encodes entropy of 16, 20, 24, 28 or 32 bytes as a BIP39 mnemonic of 12 to 24 words.
+// The last word includes a checksum of the entropy, the first bits of its SHA-256 digest.
+func FromEntropy(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("entropy must be 16, 20, 24, 28 or 32 bytes, got %d", len(entropy))
+	}
+
+	return words(checksummed(entropy)), nil
+}
+
+// ToEntropy decodes a BIP39 mnemonic of 12, 15, 18, 21 or 24 words and verifies its checksum.
+func ToEntropy(phrase string) ([]byte, error) {
+	indices, err := parse(phrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indices) < 12 || len(indices) > 24 || len(indices)%3 != 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("mnemonic must have 12, 15, 18, 21 or 24 words, got %d", len(indices))
+	}
+
+	// Every 3 words carry 32 bits of entropy and 1 bit of checksum.
+	data, _ := unpack(indices)
+	entropy := data[:len(indices)*4/3]
+
+	if !slices.Equal(checksummed(entropy), indices) {
+		return nil, ErrChecksum
+	}
+
+	return entropy, nil
+}
+
+// checksummed returns the word indices of the entropy followed by its checksum bits.
+func checksummed(entropy []byte) []int {
+	sum := sha256.Sum256(entropy)
+
+	// The checksum has one bit per 32 bits of entropy, at most 8, so its first byte is enough.
+	indices := pack(append(bytes.Clone(entropy), sum[0]))
+
+	return indices[:len(entropy)*3/4]
+}
+
+// Encode encodes the data as space-separated words.
+// The first word encodes the length of the data, the remaining words its bits,
+// where the last word is padded with zero bits.
+func Encode(data []byte) (string, error) {
+	if len(data) > MaxLength {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("data must be at most %d bytes, got %d", MaxLength, len(data))
+	}
+
+	return words(append([]int{len(data)}, pack(data)...)), nil
+}
+
+// Decode decodes words produced by Encode.
+func Decode(phrase string) ([]byte, error) {
+	indices, err := parse(phrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indices) == 0 {
+		return nil, errors.New("empty mnemonic") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	length, indices := indices[0], indices[1:]
+
+	if want := (length*8 + bitsPerWord - 1) / bitsPerWord; len(indices) != want {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("mnemonic of %d bytes must have %d words after the length word, got %d",
+			length, want, len(indices))
+	}
+
+	// The padding of the last word may fill a whole byte, which must be zero as well.
+	data, padding := unpack(indices)
+	if padding != 0 || strings.Trim(string(data[length:]), "\x00") != "" {
+		return nil, errors.New("invalid mnemonic padding") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	return data[:length], nil
+}
+
+// pack splits the bits of the data into word indices, padding the last index with zero bits.
+func pack(data []byte) []int {
+	indices := make([]int, 0, (len(data)*8+bitsPerWord-1)/bitsPerWord)
+
+	accumulator, bits := 0, 0
+
+	for _, b := range data {
+		accumulator = accumulator<<8 | int(b)
+		bits += 8
+
+		for bits >= bitsPerWord {
+			bits -= bitsPerWord
+			indices = append(indices, accumulator>>bits)
+			accumulator &= 1<<bits - 1
+		}
+	}
+
+	if bits > 0 {
+		indices = append(indices, accumulator<<(bitsPerWord-bits))
+	}
+
+	return indices
+}
+
+// unpack joins the bits of word indices into bytes.
+// It returns the bytes and the value of the remaining bits that do not fill a byte.
+func unpack(indices []int) ([]byte, int) {
+	data := make([]byte, 0, len(indices)*bitsPerWord/8)
+
+	accumulator, bits := 0, 0
+
+	for _, index := range indices {
+		accumulator = accumulator<<bitsPerWord | index
+		bits += bitsPerWord
+
+		for bits >= 8 {
+			bits -= 8
+			data = append(data, byte(accumulator>>bits))
+			accumulator &= 1<<bits - 1
+		}
+	}
+
+	return data, accumulator
+}
+
+// words returns the words of the indices, separated by spaces.
+func words(indices []int) string {
+	phrase := make([]string, len(indices))
+
+	for i, index := range indices {
+		phrase[i] = wordlist[index]
+	}
+
+	return strings.Join(phrase, " ")
+}
+
+// parse returns the word indices of a phrase.
+func parse(phrase string) ([]int, error) {
+	fields := strings.Fields(strings.ToLower(phrase))
+	indices := make([]int, len(fields))
+
+	for i, word := range fields {
+		index, ok := lookup[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q (word %d)", ErrWord, word, i+1)
+		}
+
+		indices[i] = index
+	}
+
+	return indices, nil
+}
+
+// indexWords maps the words and their unique prefixes to their indices.
+func indexWords(words []string) map[string]int {
+	lookup := make(map[string]int, 2*len(words)) //nolint:mnd	// Words and prefixes.
+
+	for index, word := range words {
+		lookup[word] = index
+
+		if len(word) > prefixLength {
+			lookup[word[:prefixLength]] = index
+		}
+	}
+
+	return lookup
+}
@@ -0,0 +1,161 @@
+package mnemonic_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/mnemonic"
+)
+
+// vectors are test vectors of the BIP39 reference implementation (https://github.com/trezor/python-mnemonic).
+//
+//nolint:gochecknoglobals	// Constant test vectors.
+var vectors = []struct {
+	entropy string
+	phrase  string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+	},
+	{
+		"9e885d952ad362caeb4efe34a8e91bd2",
+		"ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon " +
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+	},
+}
+
+// TestFromEntropy encodes the entropy of the BIP39 test vectors.
+func TestFromEntropy(t *testing.T) {
+	t.Parallel()
+
+	for _, vector := range vectors {
+		entropy, err := hex.DecodeString(vector.entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		phrase, err := mnemonic.FromEntropy(entropy)
+		if err != nil {
+			t.Fatalf("%s: %v", vector.entropy, err)
+		}
+
+		if phrase != vector.phrase {
+			t.Errorf("FromEntropy(%s) = %q, want %q", vector.entropy, phrase, vector.phrase)
+		}
+	}
+}
+
+// TestToEntropy decodes the mnemonics of the BIP39 test vectors, also in uppercase and abbreviated.
+func TestToEntropy(t *testing.T) {
+	t.Parallel()
+
+	abbreviate := func(phrase string) string {
+		words := strings.Fields(phrase)
+
+		for i, word := range words {
+			words[i] = word[:min(len(word), 4)]
+		}
+
+		return strings.Join(words, " ")
+	}
+
+	for _, vector := range vectors {
+		for _, phrase := range []string{vector.phrase, strings.ToUpper(vector.phrase), abbreviate(vector.phrase)} {
+			entropy, err := mnemonic.ToEntropy(phrase)
+			if err != nil {
+				t.Fatalf("%q: %v", phrase, err)
+			}
+
+			if got := hex.EncodeToString(entropy); got != vector.entropy {
+				t.Errorf("ToEntropy(%q) = %s, want %s", phrase, got, vector.entropy)
+			}
+		}
+	}
+}
+
+// TestToEntropyErrors rejects mnemonics with a wrong checksum or unknown words.
+func TestToEntropyErrors(t *testing.T) {
+	t.Parallel()
+
+	checksum := strings.Replace(vectors[0].phrase, "about", "abandon", 1)
+	if _, err := mnemonic.ToEntropy(checksum); !errors.Is(err, mnemonic.ErrChecksum) {
+		t.Errorf("wrong checksum: error = %v, want %v", err, mnemonic.ErrChecksum)
+	}
+
+	unknown := strings.Replace(vectors[1].phrase, "legal", "gogen", 1)
+	if _, err := mnemonic.ToEntropy(unknown); !errors.Is(err, mnemonic.ErrWord) {
+		t.Errorf("unknown word: error = %v, want %v", err, mnemonic.ErrWord)
+	}
+}
+
+// TestEncodeDecode round-trips data of every length up to 100 bytes.
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	for length := range 101 {
+		data := make([]byte, length)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+
+		phrase, err := mnemonic.Encode(data)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", length, err)
+		}
+
+		decoded, err := mnemonic.Decode(phrase)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", length, err)
+		}
+
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("%d bytes: Decode(Encode(%x)) = %x", length, data, decoded)
+		}
+	}
+}
+
+// TestEncodeKnownAnswer checks the encoding against a known answer: the length word, then the bits of the data.
+func TestEncodeKnownAnswer(t *testing.T) {
+	t.Parallel()
+
+	// Length 2 is the third word, 0xffff is 11 one bits (zoo), then 5 one bits padded with zeros (index 1984).
+	const want = "able zoo way"
+
+	phrase, err := mnemonic.Encode([]byte{0xff, 0xff})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if phrase != want {
+		t.Errorf("Encode(ffff) = %q, want %q", phrase, want)
+	}
+
+	if _, err := mnemonic.Decode("able zoo zoo"); err == nil {
+		t.Error("Decode with non-zero padding succeeded")
+	}
+}
@@ -0,0 +1,172 @@
+package otp_test
+
+import (
+	"encoding/base32"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/idelchi/gogen/pkg/otp"
+)
+
+// TestNewSecret checks that secrets are unpadded base32 of the requested size.
+func TestNewSecret(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{10, 20, 32, 64} {
+		secret, err := otp.NewSecret(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(secret, "=") {
+			t.Errorf("NewSecret(%d) = %s is padded", size, secret)
+		}
+
+		decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+		if err != nil {
+			t.Fatalf("NewSecret(%d) = %s: %v", size, secret, err)
+		}
+
+		if len(decoded) != size {
+			t.Errorf("NewSecret(%d) decodes to %d bytes", size, len(decoded))
+		}
+	}
+}
+
+// TestURI checks key URIs against the Key Uri Format of Google Authenticator.
+func TestURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		key  otp.Key
+		want string
+	}{
+		{
+			name: "totp with issuer",
+			key: otp.Key{
+				Type:      otp.TOTP,
+				Issuer:    "ACME Co",
+				Account:   "john.doe@email.com",
+				Secret:    "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ",
+				Algorithm: otp.SHA1,
+				Digits:    6,
+				Period:    30,
+			},
+			want: "otpauth://totp/ACME%20Co:john.doe@email.com" +
+				"?algorithm=SHA1&digits=6&issuer=ACME%20Co&period=30&secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ",
+		},
+		{
+			name: "hotp with a slash in the account",
+			key: otp.Key{
+				Type:      otp.HOTP,
+				Account:   "ops/ci",
+				Secret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+				Algorithm: otp.SHA256,
+				Digits:    8,
+				Counter:   7,
+			},
+			want: "otpauth://hotp/ops%2Fci?algorithm=SHA256&counter=7&digits=8&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.key.URI(); got != test.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", test.name, got, test.want)
+		}
+	}
+}
+
+// TestComputeRFC4226 checks HOTP codes against the test values of RFC 4226, appendix D.
+func TestComputeRFC4226(t *testing.T) {
+	t.Parallel()
+
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range want {
+		got, err := otp.Compute([]byte("12345678901234567890"), uint64(counter), 6, otp.SHA1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != code {
+			t.Errorf("counter %d: code = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+// TestCodeRFC6238 checks TOTP codes against the test vectors of RFC 6238, appendix B,
+// with the secrets of the reference implementation encoded as base32.
+func TestCodeRFC6238(t *testing.T) {
+	t.Parallel()
+
+	secrets := map[otp.Algorithm]string{
+		otp.SHA1:   base32.StdEncoding.EncodeToString([]byte("12345678901234567890")),
+		otp.SHA256: base32.StdEncoding.EncodeToString([]byte("12345678901234567890123456789012")),
+		otp.SHA512: base32.StdEncoding.EncodeToString(
+			[]byte("1234567890123456789012345678901234567890123456789012345678901234")),
+	}
+
+	tests := []struct {
+		unix  int64
+		codes map[otp.Algorithm]string
+	}{
+		{59, map[otp.Algorithm]string{otp.SHA1: "94287082", otp.SHA256: "46119246", otp.SHA512: "90693936"}},
+		{1111111109, map[otp.Algorithm]string{otp.SHA1: "07081804", otp.SHA256: "68084774", otp.SHA512: "25091201"}},
+		{1111111111, map[otp.Algorithm]string{otp.SHA1: "14050471", otp.SHA256: "67062674", otp.SHA512: "99943326"}},
+		{1234567890, map[otp.Algorithm]string{otp.SHA1: "89005924", otp.SHA256: "91819424", otp.SHA512: "93441116"}},
+		{2000000000, map[otp.Algorithm]string{otp.SHA1: "69279037", otp.SHA256: "90698825", otp.SHA512: "38618901"}},
+		{20000000000, map[otp.Algorithm]string{otp.SHA1: "65353130", otp.SHA256: "77737706", otp.SHA512: "47863826"}},
+	}
+
+	for _, test := range tests {
+		for algorithm, want := range test.codes {
+			key := otp.Key{Type: otp.TOTP, Secret: secrets[algorithm], Algorithm: algorithm, Digits: 8, Period: 30}
+
+			got, err := key.Code(time.Unix(test.unix, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != want {
+				t.Errorf("%s at %d: code = %s, want %s", algorithm, test.unix, got, want)
+			}
+		}
+	}
+}
+
+// TestCheck checks the accepted drift of TOTP and HOTP codes.
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	// Lowercase, grouped and padded secrets are accepted.
+	secret := "gezd gnbv gy3t qojq gezd gnbv gy3t qojq===="
+
+	totp := otp.Key{Type: otp.TOTP, Secret: secret, Algorithm: otp.SHA1, Digits: 6, Period: 30}
+
+	// 287082 is the code of step 1, i.e. of the times 30 to 59.
+	for unix, want := range map[int64]int{45: 0, 75: -1, 15: 1} {
+		offset, err := totp.Check("287082", time.Unix(unix, 0), 1)
+		if err != nil || offset != want {
+			t.Errorf("TOTP at %d: offset = %d, error = %v, want offset %d", unix, offset, err, want)
+		}
+	}
+
+	if _, err := totp.Check("287082", time.Unix(105, 0), 1); !errors.Is(err, otp.ErrInvalidCode) {
+		t.Errorf("TOTP outside of the window: error = %v, want %v", err, otp.ErrInvalidCode)
+	}
+
+	hotp := otp.Key{Type: otp.HOTP, Secret: secret, Algorithm: otp.SHA1, Digits: 6, Counter: 3}
+
+	// 338314 is the code of counter 4, ahead of the key's counter.
+	if offset, err := hotp.Check("338314", time.Time{}, 2); err != nil || offset != 1 {
+		t.Errorf("HOTP ahead: offset = %d, error = %v, want offset 1", offset, err)
+	}
+
+	// 359152 is the code of counter 2, behind the key's counter.
+	if _, err := hotp.Check("359152", time.Time{}, 2); !errors.Is(err, otp.ErrInvalidCode) {
+		t.Errorf("HOTP behind: error = %v, want %v", err, otp.ErrInvalidCode)
+	}
+}
@@ -0,0 +1,101 @@
+// Package output provides formatting of generated values for consumption by scripts.
+//
+// Values can be written as separated text, optionally labelled with names, or as a JSON array.
+//
+// Example usage:
+//
+//	entries := []output.Entry{
+//	    {Name: "alice", Value: "secret1"},
+//	    {Name: "bob", Value: "secret2"},
+//	}
+//
+//	// Write "alice<TAB>secret1\nbob<TAB>secret2\n"
+//	err := output.Write(os.Stdout, entries, output.Text, "\n")
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the output format of the values.
+type Format string
+
+const (
+	// Text writes one value per entry, terminated by a separator.
+	// Named entries are written as name and value separated by a tab.
+	Text Format = "text"
+
+	// JSON writes a JSON array of strings, or of objects with name and value for named entries.
+	JSON Format = "json"
+)
+
+// Entry is a generated value with an optional name.
+type Entry struct {
+	// Name labels the value, e.g. the user a credential is generated for
+	Name string `json:"name"`
+
+	// Value is the generated value
+	Value string `json:"value"`
+}
+
+// Write writes the entries to the writer in the given format.
+// For the text format, every entry is terminated by the separator.
+func Write(writer io.Writer, entries []Entry, format Format, separator string) error {
+	switch format {
+	case Text:
+		var builder strings.Builder
+
+		for _, entry := range entries {
+			if entry.Name != "" {
+				builder.WriteString(entry.Name + "\t")
+			}
+
+			builder.WriteString(entry.Value + separator)
+		}
+
+		if _, err := io.WriteString(writer, builder.String()); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+	case JSON:
+		encoder := json.NewEncoder(writer)
+
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+
+		var err error
+
+		if named(entries) {
+			err = encoder.Encode(entries)
+		} else {
+			values := make([]string, len(entries))
+
+			for index, entry := range entries {
+				values[index] = entry.Value
+			}
+
+			err = encoder.Encode(values)
+		}
+
+		if err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q", format) //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	return nil
+}
+
+// named reports whether any of the entries has a name.
+func named(entries []Entry) bool {
+	for _, entry := range entries {
+		if entry.Name != "" {
+			return true
+		}
+	}
+
+	return false
+}
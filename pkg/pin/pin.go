@@ -0,0 +1,169 @@
+// Package pin provides generation of numeric codes, such as door codes or SIM PINs,
+// with optional rejection of weak patterns.
+//
+// Weak patterns include repeated digits, ascending or descending runs, date-like codes
+// and codes from an embedded list of frequently chosen PINs.
+//
+// Example usage:
+//
+//	rules := pin.Rules{NoRepeat: true, NoSequence: true, NoDates: true, NoCommon: true}
+//
+//	code, err := pin.Generate(6, rules)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package pin
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/idelchi/gogen/pkg/pw"
+)
+
+//go:embed common.txt
+var common string
+
+//nolint:gochecknoglobals	// Frequently chosen PINs, parsed once from the embedded file.
+var frequentPINs = parseCommon(common)
+
+// ErrWeak indicates a PIN matching a weak pattern.
+var ErrWeak = errors.New("weak pin")
+
+// maxAttempts bounds the number of candidates generated when searching for a PIN satisfying the rules.
+const maxAttempts = 100000
+
+// Rules selects the weak patterns to reject.
+type Rules struct {
+	// NoRepeat rejects PINs with the same digit twice in a row, e.g. 1123
+	NoRepeat bool
+
+	// NoSequence rejects PINs with ascending or descending runs of three digits, e.g. 1239 or 9870
+	NoSequence bool
+
+	// NoDates rejects PINs that look like a year (19xx, 20xx) or a date (DDMM, MMDD, DDMMYY, YYYYMMDD, ...)
+	NoDates bool
+
+	// NoCommon rejects frequently chosen PINs, including PINs starting with a frequently chosen shorter PIN
+	NoCommon bool
+}
+
+// Generate creates a numeric code with the given number of digits, rejecting candidates matching the rules.
+// Candidates are drawn uniformly, so the result is uniformly distributed among the acceptable PINs.
+func Generate(length int, rules Rules) (string, error) {
+	for range maxAttempts {
+		code, err := pw.GenerateFrom(length, []pw.Class{pw.Digits(0)})
+		if err != nil {
+			return "", fmt.Errorf("generating pin: %w", err)
+		}
+
+		if rules.Check(code) == nil {
+			return code, nil
+		}
+	}
+
+	//nolint:err113 // Occasional dynamic errors are fine.
+	return "", fmt.Errorf("no %d-digit pin satisfying the rules found after %d attempts", length, maxAttempts)
+}
+
+// Check returns an error wrapping ErrWeak if the PIN matches any of the selected weak patterns.
+func (r Rules) Check(code string) error {
+	switch {
+	case r.NoRepeat && repeated(code):
+		return fmt.Errorf("%w: %s repeats a digit", ErrWeak, code)
+	case r.NoSequence && sequential(code):
+		return fmt.Errorf("%w: %s contains a sequence", ErrWeak, code)
+	case r.NoDates && dateLike(code):
+		return fmt.Errorf("%w: %s looks like a date", ErrWeak, code)
+	case r.NoCommon && frequent(code):
+		return fmt.Errorf("%w: %s is frequently chosen", ErrWeak, code)
+	}
+
+	return nil
+}
+
+// repeated reports whether the code contains the same digit twice in a row.
+func repeated(code string) bool {
+	for index := 1; index < len(code); index++ {
+		if code[index] == code[index-1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sequential reports whether the code contains an ascending or descending run of three digits.
+func sequential(code string) bool {
+	for index := 2; index < len(code); index++ {
+		first := int(code[index-1]) - int(code[index-2])
+		second := int(code[index]) - int(code[index-1])
+
+		if first == second && (first == 1 || first == -1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dateLike reports whether the code looks like a year or a date.
+func dateLike(code string) bool {
+	layouts := map[int][]string{
+		4: {"0102", "0201"},
+		6: {"020106", "010206", "060102", "012006", "200601"},
+		8: {"02012006", "01022006", "20060102"},
+	}
+
+	if len(code) == 4 && (strings.HasPrefix(code, "19") || strings.HasPrefix(code, "20")) {
+		return true
+	}
+
+	for _, layout := range layouts[len(code)] {
+		// Day-month layouts without a year are checked against a leap year, to accept 29 February.
+		value, parsed := code, layout
+		if !strings.Contains(layout, "06") {
+			value, parsed = code+"2000", layout+"2006"
+		}
+
+		if date, err := time.Parse(parsed, value); err == nil && date.Format(parsed) == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// frequent reports whether the code is, or starts with, a frequently chosen PIN.
+func frequent(code string) bool {
+	for _, pin := range frequentPINs {
+		if strings.HasPrefix(code, pin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseCommon returns the PINs of the list, one per line, skipping empty lines and comments.
+func parseCommon(list string) []string {
+	var pins []string
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pins = append(pins, line)
+	}
+
+	return pins
+}
@@ -0,0 +1,72 @@
+package pin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/pin"
+)
+
+// TestCheck checks every rule against PINs matching only that rule, and PINs matching none.
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		rules pin.Rules
+		weak  []string
+	}{
+		{"repeat", pin.Rules{NoRepeat: true}, []string{"1123", "9070553", "482200"}},
+		{"sequence", pin.Rules{NoSequence: true}, []string{"1239", "9870", "40567", "18765"}},
+		{
+			"dates", pin.Rules{NoDates: true},
+			[]string{"1970", "2024", "3112", "1231", "2902", "311299", "123199", "991231", "31121999", "19991231"},
+		},
+		{"common", pin.Rules{NoCommon: true}, []string{"2580", "5683", "147258", "123456", "25801"}},
+	}
+
+	// These PINs match none of the patterns.
+	strong := []string{"4739", "5830", "3092", "0230", "472916", "30921975"}
+
+	for _, test := range tests {
+		for _, code := range test.weak {
+			if err := test.rules.Check(code); !errors.Is(err, pin.ErrWeak) {
+				t.Errorf("%s: Check(%s) = %v, want %v", test.name, code, err, pin.ErrWeak)
+			}
+
+			if err := (pin.Rules{}).Check(code); err != nil {
+				t.Errorf("no rules: Check(%s) = %v", code, err)
+			}
+		}
+
+		for _, code := range strong {
+			if err := test.rules.Check(code); err != nil {
+				t.Errorf("%s: Check(%s) = %v, want nil", test.name, code, err)
+			}
+		}
+	}
+}
+
+// TestGenerate generates PINs of common lengths satisfying all rules.
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	rules := pin.Rules{NoRepeat: true, NoSequence: true, NoDates: true, NoCommon: true}
+
+	for _, length := range []int{4, 6, 8} {
+		for range 100 {
+			code, err := pin.Generate(length, rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(code) != length {
+				t.Fatalf("Generate(%d) = %s", length, code)
+			}
+
+			if err := rules.Check(code); err != nil {
+				t.Fatalf("Generate(%d) = %s: %v", length, code, err)
+			}
+		}
+	}
+}
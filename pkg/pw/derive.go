@@ -0,0 +1,95 @@
+package pw
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/idelchi/gogen/pkg/argon"
+)
+
+// derivationVersion domain-separates derived passwords and versions the derivation scheme.
+const derivationVersion = "gogen/derive/v1"
+
+// derivationParams are the Argon2id parameters of the derivation scheme.
+// They are part of the scheme: changing them changes every derived password.
+//
+//nolint:gochecknoglobals,mnd	// Fixed parameters of the derivation scheme.
+var derivationParams = argon.Params{Iterations: 3, Memory: 64 * 1024, Parallelism: 4}
+
+// Derivation identifies a password derived deterministically from a master secret,
+// in the spirit of stateless password managers: the same inputs always yield the same password,
+// without storing anything.
+type Derivation struct {
+	// Site is the site or service the password is for, compared case-insensitively
+	Site string
+
+	// Login is the user name or email address on the site
+	Login string
+
+	// Counter is incremented to rotate the password of a site and login
+	Counter uint64
+}
+
+// Random returns the deterministic source of randomness of the derivation, to be used
+// with GenerateWith or Policy.GenerateWith.
+//
+// The master secret is stretched with Argon2id, salted with the site and login, and
+// bound to the counter with HKDF-SHA256. The resulting key seeds a ChaCha20 keystream,
+// so generation can consume as much randomness as it needs, e.g. for rejection sampling.
+func (d Derivation) Random(master []byte) (io.Reader, error) {
+	if len(master) == 0 {
+		return nil, errors.New("master secret must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	site := strings.ToLower(strings.TrimSpace(d.Site))
+	if site == "" {
+		return nil, errors.New("site must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	}
+
+	// Length-prefix the fields, so that different site and login splits never share a salt.
+	salt := sha256.New()
+
+	for _, field := range []string{derivationVersion, site, strings.TrimSpace(d.Login)} {
+		_ = binary.Write(salt, binary.BigEndian, uint32(len(field))) //nolint:gosec	// Fields are short strings.
+		salt.Write([]byte(field))
+	}
+
+	const keySize = chacha20.KeySize
+
+	stretched := argon.Key(master, salt.Sum(nil), keySize, derivationParams)
+
+	info := binary.BigEndian.AppendUint64([]byte(derivationVersion), d.Counter)
+
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, stretched, nil, info), key); err != nil {
+		return nil, fmt.Errorf("expanding key: %w", err)
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		return nil, fmt.Errorf("creating keystream: %w", err)
+	}
+
+	return keystream{cipher: cipher}, nil
+}
+
+// keystream is an endless reader of ChaCha20 keystream bytes.
+type keystream struct {
+	cipher *chacha20.Cipher
+}
+
+// Read fills the buffer with the next keystream bytes.
+func (k keystream) Read(buffer []byte) (int, error) {
+	clear(buffer)
+
+	k.cipher.XORKeyStream(buffer, buffer)
+
+	return len(buffer), nil
+}
@@ -0,0 +1,142 @@
+package pw_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/idelchi/gogen/pkg/pw"
+)
+
+// master is the master secret of the tests.
+const master = "correct horse"
+
+// keystream returns the first 32 bytes of randomness of the derivation.
+func keystream(t *testing.T, derivation pw.Derivation) []byte {
+	t.Helper()
+
+	random, err := derivation.Random([]byte(master))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := make([]byte, 32)
+	if _, err := io.ReadFull(random, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	return stream
+}
+
+// TestDeriveKnownAnswer pins the derivation scheme, as changing it would change every derived password.
+func TestDeriveKnownAnswer(t *testing.T) {
+	t.Parallel()
+
+	derivation := pw.Derivation{Site: "example.com", Login: "me@example.com", Counter: 1}
+
+	const want = "c41d2b23e8be0cee28fef2456931569d9ed6c4fb2f55e262f7d641ae5c2532e4"
+
+	if got := hex.EncodeToString(keystream(t, derivation)); got != want {
+		t.Errorf("keystream = %s, want %s", got, want)
+	}
+
+	random, err := derivation.Random([]byte(master))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	classes := []pw.Class{pw.Lower(1), pw.Upper(1), pw.Digits(1), pw.Special(pw.DefaultSpecial(), 1)}
+
+	password, err := pw.GenerateWith(random, 16, classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if password != "um.tsp0xLLT#csWP" {
+		t.Errorf("password = %q, want %q", password, "um.tsp0xLLT#csWP")
+	}
+}
+
+// TestDeriveConstruction rebuilds the keystream of the known answer without this package:
+// Argon2id of the master secret salted with the hash of the length-prefixed version, site and login,
+// expanded with HKDF-SHA256 bound to the counter, as key of a ChaCha20 keystream with a zero nonce.
+func TestDeriveConstruction(t *testing.T) {
+	t.Parallel()
+
+	const version = "gogen/derive/v1"
+
+	salt := sha256.New()
+
+	for _, field := range []string{version, "example.com", "me@example.com"} {
+		_ = binary.Write(salt, binary.BigEndian, uint32(len(field)))
+		salt.Write([]byte(field))
+	}
+
+	stretched := argon2.IDKey([]byte(master), salt.Sum(nil), 3, 64*1024, 4, chacha20.KeySize)
+
+	key := make([]byte, chacha20.KeySize)
+
+	info := binary.BigEndian.AppendUint64([]byte(version), 1)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, stretched, nil, info), key); err != nil {
+		t.Fatal(err)
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]byte, 32)
+	cipher.XORKeyStream(want, want)
+
+	got := keystream(t, pw.Derivation{Site: "example.com", Login: "me@example.com", Counter: 1})
+	if !bytes.Equal(got, want) {
+		t.Errorf("keystream = %x, want %x", got, want)
+	}
+}
+
+// TestDeriveInputs checks which inputs change the derived randomness.
+func TestDeriveInputs(t *testing.T) {
+	t.Parallel()
+
+	base := keystream(t, pw.Derivation{Site: "example.com", Login: "me", Counter: 1})
+
+	same := []pw.Derivation{
+		{Site: "Example.COM", Login: "me", Counter: 1},
+		{Site: " example.com ", Login: " me ", Counter: 1},
+	}
+
+	for _, derivation := range same {
+		if !bytes.Equal(keystream(t, derivation), base) {
+			t.Errorf("%+v: keystream differs", derivation)
+		}
+	}
+
+	different := []pw.Derivation{
+		{Site: "example.org", Login: "me", Counter: 1},
+		{Site: "example.com", Login: "Me", Counter: 1},
+		{Site: "example.com", Login: "me", Counter: 2},
+		{Site: "example.comm", Login: "e", Counter: 1},
+	}
+
+	for _, derivation := range different {
+		if bytes.Equal(keystream(t, derivation), base) {
+			t.Errorf("%+v: keystream does not differ", derivation)
+		}
+	}
+
+	if _, err := (pw.Derivation{Site: "example.com"}).Random(nil); err == nil {
+		t.Error("empty master secret succeeded")
+	}
+
+	if _, err := (pw.Derivation{Site: " "}).Random([]byte(master)); err == nil {
+		t.Error("empty site succeeded")
+	}
+}
@@ -0,0 +1,257 @@
+package pw
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// charSetVowelsLower defines the set of lowercase vowels.
+	charSetVowelsLower = "aeiou"
+
+	// charSetConsonantsLower defines the set of lowercase consonants.
+	charSetConsonantsLower = "bcdfghjklmnpqrstvwxyz"
+
+	// charSetHexLower defines the set of lowercase hexadecimal digits.
+	charSetHexLower = "0123456789abcdef"
+)
+
+// allChars combines all character sets.
+const allChars = charSetLower + charSetUpper + charSetNumbers + charSetSpecial
+
+// PatternSyntax describes the placeholders understood by ParsePattern.
+const PatternSyntax = `c/C  lowercase/uppercase consonant
+v/V  lowercase/uppercase vowel
+l/L  lowercase/uppercase letter
+a    letter
+9    digit
+h/H  lowercase/uppercase hexadecimal digit
+!    special character
+x    any letter, digit or special character
+[..] one character of the set, e.g. [a-f0-9_]
+{n}  repeat the previous element n times
+\    escape the next character
+     any other character is used literally`
+
+// maxRepeat bounds the repetition count of a pattern element.
+const maxRepeat = 1024
+
+// Pattern is a parsed password pattern, holding the character set of every position.
+type Pattern []string
+
+// ParsePattern parses a pattern into the character sets of its positions.
+// See PatternSyntax for the supported placeholders.
+//
+//nolint:gocognit,cyclop	// Parser complexity is acceptable.
+func ParsePattern(pattern string) (Pattern, error) {
+	var result Pattern
+
+	for index := 0; index < len(pattern); index++ {
+		char := pattern[index]
+
+		switch {
+		case char == '\\':
+			if index+1 == len(pattern) {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return nil, errors.New("pattern ends with an unfinished escape")
+			}
+
+			index++
+
+			result = append(result, string(pattern[index]))
+		case char == '[':
+			end := closing(pattern, index)
+			if end < 0 {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return nil, fmt.Errorf("unterminated character set at position %d", index)
+			}
+
+			set, err := parseSet(pattern[index+1 : end])
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, set)
+			index = end
+		case char == '{':
+			end := strings.IndexByte(pattern[index:], '}')
+			if end < 0 {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return nil, fmt.Errorf("unterminated repetition at position %d", index)
+			}
+
+			if len(result) == 0 {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return nil, errors.New("repetition without a preceding element")
+			}
+
+			count, err := strconv.Atoi(pattern[index+1 : index+end])
+			if err != nil || count < 1 || count > maxRepeat {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return nil, fmt.Errorf("invalid repetition %q: must be a number between 1 and %d",
+					pattern[index:index+end+1], maxRepeat)
+			}
+
+			for range count - 1 {
+				result = append(result, result[len(result)-1])
+			}
+
+			index += end
+		case char >= 0x80: //nolint:mnd	// Upper bound of ASCII.
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return nil, errors.New("pattern must only contain ASCII characters")
+		default:
+			if set, ok := placeholder(char); ok {
+				result = append(result, set)
+			} else {
+				result = append(result, string(char))
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, errors.New("pattern must not be empty")
+	}
+
+	return result, nil
+}
+
+// Generate creates a password matching the pattern.
+// Every position is drawn independently and uniformly from its character set,
+// so all passwords matching the pattern are equally likely.
+func (p Pattern) Generate() (string, error) {
+	result := make([]byte, len(p))
+
+	for index, set := range p {
+		idx, err := secureRandomInt(len(set))
+		if err != nil {
+			return "", err
+		}
+
+		result[index] = set[idx]
+	}
+
+	return string(result), nil
+}
+
+// Entropy returns the entropy in bits of passwords generated from the pattern.
+func (p Pattern) Entropy() float64 {
+	var bits float64
+
+	for _, set := range p {
+		bits += math.Log2(float64(len(set)))
+	}
+
+	return bits
+}
+
+// placeholder returns the character set of a placeholder character.
+//
+//nolint:cyclop	// Lookup table.
+func placeholder(char byte) (string, bool) {
+	switch char {
+	case 'c':
+		return charSetConsonantsLower, true
+	case 'C':
+		return strings.ToUpper(charSetConsonantsLower), true
+	case 'v':
+		return charSetVowelsLower, true
+	case 'V':
+		return strings.ToUpper(charSetVowelsLower), true
+	case 'l':
+		return charSetLower, true
+	case 'L':
+		return charSetUpper, true
+	case 'a':
+		return charSetLower + charSetUpper, true
+	case '9':
+		return charSetNumbers, true
+	case 'h':
+		return charSetHexLower, true
+	case 'H':
+		return strings.ToUpper(charSetHexLower), true
+	case '!':
+		return charSetSpecial, true
+	case 'x':
+		return allChars, true
+	default:
+		return "", false
+	}
+}
+
+// closing returns the index of the ']' closing the set opened at start, honouring escapes, or -1.
+func closing(pattern string, start int) int {
+	for index := start + 1; index < len(pattern); index++ {
+		switch pattern[index] {
+		case '\\':
+			index++
+		case ']':
+			return index
+		}
+	}
+
+	return -1
+}
+
+// parseSet expands the body of a character set such as "a-f0-9_" into its deduplicated characters.
+func parseSet(body string) (string, error) {
+	var (
+		set  strings.Builder
+		seen = make(map[byte]struct{})
+	)
+
+	add := func(char byte) {
+		if _, ok := seen[char]; !ok {
+			seen[char] = struct{}{}
+
+			set.WriteByte(char)
+		}
+	}
+
+	for index := 0; index < len(body); index++ {
+		char := body[index]
+
+		if char >= 0x80 { //nolint:mnd	// Upper bound of ASCII.
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return "", errors.New("character set must only contain ASCII characters")
+		}
+
+		if char == '\\' && index+1 < len(body) {
+			index++
+
+			add(body[index])
+
+			continue
+		}
+
+		// A '-' between two characters denotes a range, elsewhere it is literal.
+		if index+2 < len(body) && body[index+1] == '-' {
+			last := body[index+2]
+			if last < char || last >= 0x80 { //nolint:mnd	// Upper bound of ASCII.
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return "", fmt.Errorf("invalid range %q in character set", body[index:index+3])
+			}
+
+			for c := char; c <= last; c++ {
+				add(c)
+			}
+
+			index += 2
+
+			continue
+		}
+
+		add(char)
+	}
+
+	if set.Len() == 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", errors.New("character set must not be empty")
+	}
+
+	return set.String(), nil
+}
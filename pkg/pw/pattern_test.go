@@ -0,0 +1,111 @@
+package pw_test
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/pw"
+)
+
+// TestParsePattern checks the character sets of the positions of parsed patterns.
+func TestParsePattern(t *testing.T) {
+	t.Parallel()
+
+	const (
+		lower = "abcdefghijklmnopqrstuvwxyz"
+		upper = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	)
+
+	tests := []struct {
+		pattern string
+		want    pw.Pattern
+	}{
+		{"cv", pw.Pattern{"bcdfghjklmnpqrstvwxyz", "aeiou"}},
+		{"CV", pw.Pattern{"BCDFGHJKLMNPQRSTVWXYZ", "AEIOU"}},
+		{"lLa", pw.Pattern{lower, upper, lower + upper}},
+		{"9hH!", pw.Pattern{"0123456789", "0123456789abcdef", "0123456789ABCDEF", pw.DefaultSpecial()}},
+		{"x", pw.Pattern{lower + upper + "0123456789" + pw.DefaultSpecial()}},
+		{"9{3}-", pw.Pattern{"0123456789", "0123456789", "0123456789", "-"}},
+		{"[a-f0-9_]", pw.Pattern{"abcdef0123456789_"}},
+		{"[aab-c]", pw.Pattern{"abc"}},
+		{"[-a]", pw.Pattern{"-a"}},
+		{"[a-]", pw.Pattern{"a-"}},
+		{`[\]\-]`, pw.Pattern{"]-"}},
+		{`\9\[`, pw.Pattern{"9", "["}},
+		{"[xy]{2}", pw.Pattern{"xy", "xy"}},
+		{"id_", pw.Pattern{"i", "d", "_"}},
+	}
+
+	for _, test := range tests {
+		got, err := pw.ParsePattern(test.pattern)
+		if err != nil {
+			t.Errorf("ParsePattern(%q): %v", test.pattern, err)
+
+			continue
+		}
+
+		if !slices.Equal(got, test.want) {
+			t.Errorf("ParsePattern(%q) = %q, want %q", test.pattern, got, test.want)
+		}
+	}
+}
+
+// TestParsePatternErrors rejects malformed patterns.
+func TestParsePatternErrors(t *testing.T) {
+	t.Parallel()
+
+	invalid := []string{
+		"",
+		`ab\`,
+		"[abc",
+		"[]",
+		"[z-a]",
+		"9{3",
+		"{3}9",
+		"9{0}",
+		"9{1025}",
+		"9{x}",
+		"pässword",
+		"[ä]",
+	}
+
+	for _, pattern := range invalid {
+		if _, err := pw.ParsePattern(pattern); err == nil {
+			t.Errorf("ParsePattern(%q) succeeded", pattern)
+		}
+	}
+}
+
+// TestPatternGenerate checks that generated passwords match the pattern, and the entropy of patterns.
+func TestPatternGenerate(t *testing.T) {
+	t.Parallel()
+
+	pattern, err := pw.ParsePattern("Cvc-9{2}[xyz]")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 100 {
+		password, err := pattern.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(password) != len(pattern) {
+			t.Fatalf("Generate() = %q, want %d characters", password, len(pattern))
+		}
+
+		for index, set := range pattern {
+			if !strings.ContainsRune(set, rune(password[index])) {
+				t.Fatalf("Generate() = %q: position %d not in %q", password, index, set)
+			}
+		}
+	}
+
+	want := math.Log2(21) + math.Log2(5) + math.Log2(21) + 2*math.Log2(10) + math.Log2(3)
+	if got := pattern.Entropy(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() = %f, want %f", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+package pw
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrPolicy indicates that a password violates a policy.
+var ErrPolicy = errors.New("policy violation")
+
+// maxAttempts bounds the number of candidates generated when searching for a password satisfying a policy.
+const maxAttempts = 10000
+
+// Policy describes the rules a target system imposes on passwords, beyond the character classes.
+type Policy struct {
+	// Classes are the allowed character classes with their minimum counts
+	Classes []Class
+
+	// Forbidden lists sequences that must not appear in the password (case-insensitive)
+	Forbidden []string
+
+	// MaxRepeat is the maximum number of consecutive identical characters, 0 for no limit
+	MaxRepeat int
+
+	// StartWithLetter requires the password to start with an ASCII letter
+	StartWithLetter bool
+}
+
+// Generate creates a password of the specified length satisfying the policy.
+// Candidates are generated from the classes and rejected until one satisfies all rules,
+// which keeps the result uniformly distributed among the valid passwords.
+func (p Policy) Generate(length int) (string, error) {
+	return p.GenerateWith(rand.Reader, length)
+}
+
+// GenerateWith is like Generate, but draws the randomness from the given source.
+func (p Policy) GenerateWith(random io.Reader, length int) (string, error) {
+	for range maxAttempts {
+		password, err := GenerateWith(random, length, p.Classes)
+		if err != nil {
+			return "", err
+		}
+
+		if p.Check(password) == nil {
+			return password, nil
+		}
+	}
+
+	//nolint:err113 // Occasional dynamic errors are fine.
+	return "", fmt.Errorf("no password satisfying the policy found after %d attempts", maxAttempts)
+}
+
+// Check verifies that the password satisfies the policy.
+// It returns an error wrapping ErrPolicy describing all violations.
+func (p Policy) Check(password string) error {
+	var violations []error
+
+	counts := make([]int, len(p.Classes))
+
+	for _, char := range password {
+		allowed := false
+
+		for index, class := range p.Classes {
+			if strings.ContainsRune(class.Chars, char) {
+				counts[index]++
+				allowed = true
+			}
+		}
+
+		if !allowed {
+			violations = append(violations, fmt.Errorf("%w: character %q is not allowed", ErrPolicy, char))
+		}
+	}
+
+	for index, class := range p.Classes {
+		if counts[index] < class.Min {
+			violations = append(violations,
+				fmt.Errorf("%w: requires at least %d %s characters", ErrPolicy, class.Min, class.Name))
+		}
+	}
+
+	lowered := strings.ToLower(password)
+
+	for _, sequence := range p.Forbidden {
+		if sequence != "" && strings.Contains(lowered, strings.ToLower(sequence)) {
+			violations = append(violations, fmt.Errorf("%w: contains forbidden sequence %q", ErrPolicy, sequence))
+		}
+	}
+
+	if p.MaxRepeat > 0 && longestRun(password) > p.MaxRepeat {
+		violations = append(violations,
+			fmt.Errorf("%w: more than %d consecutive identical characters", ErrPolicy, p.MaxRepeat))
+	}
+
+	if p.StartWithLetter && (password == "" || !strings.ContainsRune(charSetLower+charSetUpper, rune(password[0]))) {
+		violations = append(violations, fmt.Errorf("%w: must start with a letter", ErrPolicy))
+	}
+
+	return errors.Join(violations...)
+}
+
+// longestRun returns the length of the longest run of consecutive identical characters.
+func longestRun(password string) int {
+	longest, current := 0, 0
+
+	var previous rune
+
+	for index, char := range password {
+		if index > 0 && char == previous {
+			current++
+		} else {
+			current = 1
+		}
+
+		previous = char
+		longest = max(longest, current)
+	}
+
+	return longest
+}
@@ -0,0 +1,168 @@
+package pw
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// onsets are the consonant units starting a syllable.
+// They only consist of consonants, so that the boundaries between syllables are unambiguous.
+//
+//nolint:gochecknoglobals // Constant lookup table.
+var onsets = []string{
+	"b", "c", "d", "f", "g", "h", "j", "k", "l", "m", "n", "p", "r", "s", "t", "v", "w", "z",
+	"bl", "br", "ch", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "kl", "kr", "ph", "pl", "pr",
+	"sc", "sh", "sk", "sl", "sm", "sn", "sp", "st", "sw", "th", "tr", "tw",
+}
+
+// nuclei are the vowel units of a syllable.
+//
+//nolint:gochecknoglobals // Constant lookup table.
+var nuclei = []string{
+	"a", "e", "i", "o", "u",
+	"ai", "au", "ea", "ee", "ia", "ie", "io", "oa", "oi", "oo", "ou",
+}
+
+// Pronounceable holds the parameters for pronounceable password generation,
+// loosely following FIPS 181: passwords are made of consonant-vowel syllables,
+// with capitalized syllables, digits and special characters mixed in between.
+type Pronounceable struct {
+	// Syllables is the number of syllables
+	Syllables int
+
+	// Upper is the number of syllables starting with an uppercase letter
+	Upper int
+
+	// Digits is the number of digits placed between syllables
+	Digits int
+
+	// Special is the number of special characters placed between syllables
+	Special int
+
+	// Symbols is the set of special characters, the default set if empty
+	Symbols string
+}
+
+// Generate creates a pronounceable password.
+// Syllables, digits and special characters are drawn independently and their order is shuffled,
+// so that digits and special characters appear at uniformly random syllable boundaries.
+func (p Pronounceable) Generate() (string, error) {
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
+	tokens := make([]string, 0, p.Syllables+p.Digits+p.Special)
+
+	for range p.Syllables {
+		onset, err := secureRandomInt(len(onsets))
+		if err != nil {
+			return "", err
+		}
+
+		nucleus, err := secureRandomInt(len(nuclei))
+		if err != nil {
+			return "", err
+		}
+
+		tokens = append(tokens, onsets[onset]+nuclei[nucleus])
+	}
+
+	// Capitalize distinct random syllables by shuffling their indices.
+	indices := make([]int, p.Syllables)
+	for index := range indices {
+		indices[index] = index
+	}
+
+	for index := len(indices) - 1; index > 0; index-- {
+		j, err := secureRandomInt(index + 1)
+		if err != nil {
+			return "", err
+		}
+
+		indices[index], indices[j] = indices[j], indices[index]
+	}
+
+	for _, index := range indices[:p.Upper] {
+		tokens[index] = capitalize(tokens[index])
+	}
+
+	for _, extra := range []struct {
+		count int
+		chars string
+	}{
+		{p.Digits, charSetNumbers},
+		{p.Special, p.symbols()},
+	} {
+		for range extra.count {
+			idx, err := secureRandomInt(len(extra.chars))
+			if err != nil {
+				return "", err
+			}
+
+			tokens = append(tokens, string(extra.chars[idx]))
+		}
+	}
+
+	for index := len(tokens) - 1; index > 0; index-- {
+		j, err := secureRandomInt(index + 1)
+		if err != nil {
+			return "", err
+		}
+
+		tokens[index], tokens[j] = tokens[j], tokens[index]
+	}
+
+	return strings.Join(tokens, ""), nil
+}
+
+// Entropy returns the entropy in bits of the generated passwords.
+// It accounts for the choice of syllables, capitalized syllables, digits and special characters,
+// as well as for the positions of the digits and special characters.
+func (p Pronounceable) Entropy() float64 {
+	extras := p.Digits + p.Special
+
+	return float64(p.Syllables)*math.Log2(float64(len(onsets)*len(nuclei))) +
+		log2Binomial(p.Syllables, p.Upper) +
+		float64(p.Digits)*math.Log2(float64(len(charSetNumbers))) +
+		float64(p.Special)*math.Log2(float64(len(p.symbols()))) +
+		log2Binomial(p.Syllables+extras, extras) +
+		log2Binomial(extras, p.Digits)
+}
+
+// symbols returns the set of special characters.
+func (p Pronounceable) symbols() string {
+	if p.Symbols == "" {
+		return charSetSpecial
+	}
+
+	return p.Symbols
+}
+
+// validate checks the parameters for consistency.
+func (p Pronounceable) validate() error {
+	switch {
+	case p.Syllables <= 0:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return errors.New("number of syllables must be greater than 0")
+	case p.Upper < 0 || p.Digits < 0 || p.Special < 0:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return errors.New("number of uppercase syllables, digits and special characters must not be negative")
+	case p.Upper > p.Syllables:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return errors.New("number of uppercase syllables must not exceed the number of syllables")
+	}
+
+	return nil
+}
+
+// log2Binomial returns the binary logarithm of the binomial coefficient "n choose k".
+func log2Binomial(n, k int) float64 {
+	lgamma := func(x int) float64 {
+		value, _ := math.Lgamma(float64(x + 1))
+
+		return value
+	}
+
+	return (lgamma(n) - lgamma(k) - lgamma(n-k)) / math.Ln2
+}
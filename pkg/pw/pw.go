@@ -1,12 +1,18 @@
 // Package pw provides secure password generation functionality using a diverse
 // set of characters including lowercase, uppercase, numbers, and special characters.
+//
+// Passwords are generated from character classes, each of which can require a minimum
+// number of occurrences. The default classes can be narrowed down or replaced by custom ones.
 package pw
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
-	"math/big"
+	"io"
+	"math"
+	"strings"
 )
 
 const (
@@ -24,79 +30,260 @@ const (
 	// no pipes, redirects, globs, quotes, escapes, or command substitutions.
 	charSetSpecial = "@#%^_+-=:,."
 
-	// allChars combines all character sets for password generation.
-	allChars = charSetLower + charSetUpper + charSetNumbers + charSetSpecial
+	// Ambiguous defines characters that are easily confused with each other when read.
+	Ambiguous = "0O1lI"
 )
 
+// Class is a named set of characters, of which a password must contain at least Min.
+type Class struct {
+	// Name identifies the class in error messages
+	Name string
+
+	// Chars are the characters of the class
+	Chars string
+
+	// Min is the minimum number of characters from the class
+	Min int
+}
+
+// Lower returns the class of lowercase ASCII letters.
+func Lower(minimum int) Class {
+	return Class{Name: "lower", Chars: charSetLower, Min: minimum}
+}
+
+// Upper returns the class of uppercase ASCII letters.
+func Upper(minimum int) Class {
+	return Class{Name: "upper", Chars: charSetUpper, Min: minimum}
+}
+
+// Digits returns the class of decimal digits.
+func Digits(minimum int) Class {
+	return Class{Name: "digits", Chars: charSetNumbers, Min: minimum}
+}
+
+// Special returns the class of special characters.
+// An empty chars uses the default set of special characters, which is safe for command-line usage.
+func Special(chars string, minimum int) Class {
+	if chars == "" {
+		chars = charSetSpecial
+	}
+
+	return Class{Name: "special", Chars: chars, Min: minimum}
+}
+
+// DefaultSpecial returns the default set of special characters.
+func DefaultSpecial() string {
+	return charSetSpecial
+}
+
+// Exclude removes the given characters from all classes.
+// Classes left without characters are dropped, unless they require a minimum, which is an error.
+func Exclude(classes []Class, chars string) ([]Class, error) {
+	result := make([]Class, 0, len(classes))
+
+	for _, class := range classes {
+		class.Chars = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(chars, r) {
+				return -1
+			}
+
+			return r
+		}, class.Chars)
+
+		if class.Chars == "" {
+			if class.Min > 0 {
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return nil, fmt.Errorf("no characters left in class %q after exclusions", class.Name)
+			}
+
+			continue
+		}
+
+		result = append(result, class)
+	}
+
+	return result, nil
+}
+
 // secureRandomInt generates a cryptographically secure random integer in the range [0, max).
 func secureRandomInt(upperBound int) (int, error) {
-	bigInt, err := rand.Int(rand.Reader, big.NewInt(int64(upperBound)))
-	if err != nil {
-		return 0, fmt.Errorf("generating random number: %w", err)
+	return randomInt(rand.Reader, upperBound)
+}
+
+// randomInt generates a uniformly distributed integer in the range [0, max) from the given source.
+// Values are drawn as 64-bit integers and rejected above the largest multiple of max,
+// so the same source always yields the same sequence of integers.
+func randomInt(random io.Reader, upperBound int) (int, error) {
+	if upperBound <= 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return 0, fmt.Errorf("upper bound must be greater than 0, got %d", upperBound)
 	}
 
-	return int(bigInt.Int64()), nil
+	bound := uint64(upperBound)
+	remainder := (math.MaxUint64%bound + 1) % bound
+
+	var buffer [8]byte
+
+	for {
+		if _, err := io.ReadFull(random, buffer[:]); err != nil {
+			return 0, fmt.Errorf("generating random number: %w", err)
+		}
+
+		if value := binary.BigEndian.Uint64(buffer[:]); value <= math.MaxUint64-remainder {
+			return int(value % bound), nil
+		}
+	}
 }
 
 // Generate creates a password of the specified length using a mix of character classes.
 // If requireAll is true, ensures at least one character from each character set.
-//
-//nolint:gocognit,nestif	// Function complexity is acceptable.
 func Generate(length int, requireAll bool) (string, error) {
+	minimum := 0
+	if requireAll {
+		minimum = 1
+	}
+
+	return GenerateFrom(length, []Class{
+		Lower(minimum),
+		Upper(minimum),
+		Digits(minimum),
+		Special("", minimum),
+	})
+}
+
+// GenerateFrom creates a password of the specified length from the given character classes.
+// It first places the minimum number of characters of each class, fills the remaining positions
+// from the union of all classes and finally shuffles the result to avoid predictable positioning.
+func GenerateFrom(length int, classes []Class) (string, error) {
+	return GenerateWith(rand.Reader, length, classes)
+}
+
+// GenerateWith is like GenerateFrom, but draws the randomness from the given source.
+// A deterministic source, such as the one of a Derivation, yields a deterministic password.
+func GenerateWith(random io.Reader, length int, classes []Class) (string, error) {
 	if length <= 0 {
 		//nolint:err113 // Occasional dynamic errors are fine.
 		return "", errors.New("length must be greater than 0")
 	}
 
-	if requireAll && length < 4 {
-		//nolint:err113 // Occasional dynamic errors are fine.
-		return "", errors.New("length must be at least 4 when requiring all character types")
+	alphabet, required, err := union(classes)
+	if err != nil {
+		return "", err
 	}
 
-	result := make([]byte, length)
+	if required > length {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("length must be at least %d to satisfy the minimum character counts", required)
+	}
 
-	if requireAll {
-		// Place one required character from each set
-		charSets := []string{charSetLower, charSetUpper, charSetNumbers, charSetSpecial}
+	result := make([]byte, 0, length)
 
-		for index, charset := range charSets {
-			idx, err := secureRandomInt(len(charset))
+	// Place the required characters from each class
+	for _, class := range classes {
+		for range class.Min {
+			idx, err := randomInt(random, len(class.Chars))
 			if err != nil {
 				return "", err
 			}
 
-			result[index] = charset[idx]
+			result = append(result, class.Chars[idx])
+		}
+	}
+
+	// Fill remaining positions
+	for len(result) < length {
+		idx, err := randomInt(random, len(alphabet))
+		if err != nil {
+			return "", err
 		}
 
-		// Fill remaining positions
-		for index := 4; index < length; index++ {
-			idx, err := secureRandomInt(len(allChars))
-			if err != nil {
-				return "", err
-			}
+		result = append(result, alphabet[idx])
+	}
 
-			result[index] = allChars[idx]
+	if err := shuffle(random, result); err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// union validates the classes and returns the deduplicated union of their characters,
+// together with the total number of required characters.
+func union(classes []Class) (string, int, error) {
+	if len(classes) == 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", 0, errors.New("at least one character class is required")
+	}
+
+	var (
+		alphabet strings.Builder
+		required int
+		seen     = make(map[byte]struct{})
+	)
+
+	for _, class := range classes {
+		if class.Chars == "" {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return "", 0, fmt.Errorf("character class %q is empty", class.Name)
 		}
 
-		// Shuffle to avoid predictable positioning
-		for index := len(result) - 1; index > 0; index-- {
-			j, err := secureRandomInt(index + 1)
-			if err != nil {
-				return "", err
+		if class.Min < 0 {
+			//nolint:err113 // Occasional dynamic errors are fine.
+			return "", 0, fmt.Errorf("minimum of character class %q must not be negative", class.Name)
+		}
+
+		for index := range len(class.Chars) {
+			char := class.Chars[index]
+
+			if char >= 0x80 { //nolint:mnd	// Upper bound of ASCII.
+				//nolint:err113 // Occasional dynamic errors are fine.
+				return "", 0, fmt.Errorf("character class %q must only contain ASCII characters", class.Name)
 			}
 
-			result[index], result[j] = result[j], result[index]
-		}
-	} else {
-		for index := range length {
-			idx, err := secureRandomInt(len(allChars))
-			if err != nil {
-				return "", err
+			if _, ok := seen[char]; ok {
+				continue
 			}
 
-			result[index] = allChars[idx]
+			seen[char] = struct{}{}
+
+			alphabet.WriteByte(char)
 		}
+
+		required += class.Min
 	}
 
-	return string(result), nil
+	return alphabet.String(), required, nil
+}
+
+// shuffle performs an in-place Fisher-Yates shuffle using the given source.
+func shuffle(random io.Reader, chars []byte) error {
+	for index := len(chars) - 1; index > 0; index-- {
+		j, err := randomInt(random, index+1)
+		if err != nil {
+			return err
+		}
+
+		chars[index], chars[j] = chars[j], chars[index]
+	}
+
+	return nil
+}
+
+// Alphabet returns the deduplicated union of the characters of the classes.
+func Alphabet(classes []Class) (string, error) {
+	alphabet, _, err := union(classes)
+
+	return alphabet, err
+}
+
+// Entropy returns the entropy in bits of passwords of the given length generated from the classes.
+// It is computed from the size of the union of the classes and is an upper bound,
+// as minimum counts slightly reduce the number of possible passwords.
+func Entropy(length int, classes []Class) float64 {
+	alphabet, _, err := union(classes)
+	if err != nil {
+		return 0
+	}
+
+	return float64(length) * math.Log2(float64(len(alphabet)))
 }
@@ -0,0 +1,29 @@
+// Package qr provides functionality for rendering QR codes locally,
+// either as Unicode block characters for the terminal or as PNG images.
+package qr
+
+import (
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Terminal renders the content as a QR code made of Unicode half-block characters.
+// Two modules are packed per character row, so the code stays readable in a terminal.
+func Terminal(content string) (string, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", fmt.Errorf("encoding qr code: %w", err)
+	}
+
+	return code.ToSmallString(false), nil
+}
+
+// PNG writes the content as a QR code PNG image of size x size pixels to the given path.
+func PNG(content string, size int, path string) error {
+	if err := qrcode.WriteFile(content, qrcode.Medium, size, path); err != nil {
+		return fmt.Errorf("writing qr code: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package secret generates secrets in the exact formats expected by web frameworks.
+//
+// Example usage:
+//
+//	value, err := secret.Generate("django")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package secret
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/idelchi/gogen/pkg/key"
+	"github.com/idelchi/gogen/pkg/pw"
+)
+
+// djangoAlphabet is the alphabet of django.core.management.utils.get_random_secret_key.
+const djangoAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789!@#$%^&*(-_=+)"
+
+// Preset describes the secret of a framework.
+type Preset struct {
+	// Name is the name of the framework
+	Name string
+
+	// Setting is the setting or environment variable the secret is usually stored in
+	Setting string
+
+	// Format describes the generated secret
+	Format string
+
+	// generate creates a new secret
+	generate func() (string, error)
+}
+
+// presets lists the supported frameworks.
+//
+//nolint:gochecknoglobals,mnd	// Constant lookup table.
+var presets = []Preset{
+	{
+		Name:    "django",
+		Setting: "SECRET_KEY",
+		Format:  "50 characters of [a-z0-9!@#$%^&*(-_=+)]",
+		generate: func() (string, error) {
+			return pw.GenerateFrom(50, []pw.Class{{Name: "alphabet", Chars: djangoAlphabet}})
+		},
+	},
+	{
+		Name:     "rails",
+		Setting:  "secret_key_base",
+		Format:   "64 bytes as 128 hex characters",
+		generate: encoded(64, key.Key.AsHex),
+	},
+	{
+		Name:    "laravel",
+		Setting: "APP_KEY",
+		Format:  "32 bytes as base64, prefixed with base64:",
+		generate: encoded(32, func(k key.Key) string {
+			return "base64:" + k.AsBase64()
+		}),
+	},
+	{
+		Name:    "fernet",
+		Setting: "Fernet key",
+		Format:  "32 bytes as padded URL-safe base64",
+		generate: encoded(32, func(k key.Key) string {
+			return base64.URLEncoding.EncodeToString(k)
+		}),
+	},
+	{
+		Name:     "flask",
+		Setting:  "SECRET_KEY",
+		Format:   "32 bytes as 64 hex characters",
+		generate: encoded(32, key.Key.AsHex),
+	},
+	{
+		Name:     "nextauth",
+		Setting:  "NEXTAUTH_SECRET / AUTH_SECRET",
+		Format:   "32 bytes as base64",
+		generate: encoded(32, key.Key.AsBase64),
+	},
+}
+
+// Presets returns the supported frameworks.
+func Presets() []Preset {
+	return presets
+}
+
+// Generate creates a new secret for the named framework.
+func Generate(framework string) (string, error) {
+	for _, preset := range presets {
+		if preset.Name == framework {
+			return preset.generate()
+		}
+	}
+
+	names := make([]string, len(presets))
+	for i, preset := range presets {
+		names[i] = preset.Name
+	}
+
+	//nolint:err113 // Occasional dynamic errors are fine.
+	return "", fmt.Errorf("unknown framework %q, supported: %v", framework, names)
+}
+
+// encoded returns a generator of random keys of the length, encoded with the function.
+func encoded(length int, encode func(key.Key) string) func() (string, error) {
+	return func() (string, error) {
+		random, err := key.New(length)
+		if err != nil {
+			return "", err //nolint:wrapcheck	// Error does not need additional wrapping.
+		}
+
+		return encode(random), nil
+	}
+}
@@ -0,0 +1,38 @@
+package shamir
+
+// mul multiplies two elements of GF(2^8) with the AES reduction polynomial x^8 + x^4 + x^3 + x + 1.
+// It runs in constant time, as the operands are secret.
+func mul(a, b byte) byte {
+	var product byte
+
+	for range 8 {
+		product ^= -(b & 1) & a
+		a = a<<1 ^ -(a>>7)&0x1b
+		b >>= 1
+	}
+
+	return product
+}
+
+// inv returns the multiplicative inverse of a non-zero element as a^254, in constant time.
+func inv(a byte) byte {
+	result := byte(1)
+
+	for range 7 {
+		a = mul(a, a)
+		result = mul(result, a)
+	}
+
+	return result
+}
+
+// evaluate returns the value of the polynomial with the coefficients (lowest degree first) at x.
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+
+	return y
+}
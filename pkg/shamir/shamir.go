@@ -0,0 +1,211 @@
+// Package shamir implements Shamir's secret sharing over GF(2^8).
+//
+// A secret is split into N shares, any K of which (the threshold) recover it,
+// while fewer than K reveal nothing about it. Every byte of the secret is shared
+// with its own random polynomial of degree K-1.
+//
+// Encoded shares carry a random identifier of their split, the threshold, their index
+// and a checksum, so that corrupted, mistyped or mixed up shares are detected before recombining.
+//
+// Example usage:
+//
+//	shares, err := shamir.Split(secret, 5, 3)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	restored, err := shamir.Combine(shares[1:4])
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxShares is the maximum number of shares, bounded by the non-zero elements of GF(2^8).
+	MaxShares = 255
+
+	// IDSize is the size of the random identifier shared by the shares of a split.
+	IDSize = 4
+
+	// headerSize is the size of the identifier, threshold and index preceding the value of an encoded share.
+	headerSize = IDSize + 2
+
+	// checksumSize is the size of the truncated SHA-256 checksum following the value of an encoded share.
+	checksumSize = 4
+)
+
+var (
+	// ErrChecksum indicates a share whose checksum does not match its content.
+	ErrChecksum = errors.New("invalid share checksum")
+
+	// ErrShares indicates shares that cannot be combined.
+	ErrShares = errors.New("invalid shares")
+)
+
+// Share is one share of a split secret.
+type Share struct {
+	// ID identifies the split the share belongs to
+	ID [IDSize]byte
+
+	// Threshold is the number of shares needed to recover the secret
+	Threshold byte
+
+	// Index is the x-coordinate of the share (1-255)
+	Index byte
+
+	// Value holds the y-coordinates of the share, one per byte of the secret
+	Value []byte
+}
+
+// Split splits the secret into the given number of shares, any threshold of which recover it.
+func Split(secret []byte, shares, threshold int) ([]Share, error) {
+	switch {
+	case len(secret) == 0:
+		return nil, errors.New("secret must not be empty") //nolint:err113 // Occasional dynamic errors are fine.
+	case threshold < 2:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("threshold must be at least 2, got %d", threshold)
+	case shares < threshold || shares > MaxShares:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("shares must be between the threshold (%d) and %d, got %d", threshold, MaxShares, shares)
+	}
+
+	var id [IDSize]byte
+
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, fmt.Errorf("generating split identifier: %w", err)
+	}
+
+	result := make([]Share, shares)
+
+	for i := range result {
+		result[i] = Share{
+			ID:        id,
+			Threshold: byte(threshold),
+			Index:     byte(i + 1),
+			Value:     make([]byte, len(secret)),
+		}
+	}
+
+	coefficients := make([]byte, threshold)
+	defer clear(coefficients)
+
+	for position, b := range secret {
+		coefficients[0] = b
+
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("generating coefficients: %w", err)
+		}
+
+		for i := range result {
+			result[i].Value[position] = evaluate(coefficients, result[i].Index)
+		}
+	}
+
+	return result, nil
+}
+
+// Combine recovers the secret from at least threshold shares of the same split.
+// Shares of different splits are rejected by their identifier.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("%w: no shares given", ErrShares)
+	}
+
+	first := shares[0]
+	seen := make(map[byte]bool, len(shares))
+
+	for _, share := range shares {
+		switch {
+		case share.Index == 0:
+			return nil, fmt.Errorf("%w: share index must not be 0", ErrShares)
+		case share.Threshold < 2:
+			return nil, fmt.Errorf("%w: threshold must be at least 2, got %d", ErrShares, share.Threshold)
+		case seen[share.Index]:
+			return nil, fmt.Errorf("%w: share %d is given more than once", ErrShares, share.Index)
+		case share.ID != first.ID || share.Threshold != first.Threshold || len(share.Value) != len(first.Value):
+			return nil, fmt.Errorf("%w: share %d belongs to a different split than share %d",
+				ErrShares, share.Index, first.Index)
+		}
+
+		seen[share.Index] = true
+	}
+
+	if len(shares) < int(first.Threshold) {
+		return nil, fmt.Errorf("%w: %d shares are needed, got %d", ErrShares, first.Threshold, len(shares))
+	}
+
+	shares = shares[:first.Threshold]
+
+	// Lagrange interpolation at x = 0, where subtraction is addition (XOR) in GF(2^8).
+	secret := make([]byte, len(first.Value))
+
+	for i, share := range shares {
+		basis := byte(1)
+
+		for j, other := range shares {
+			if i != j {
+				basis = mul(basis, mul(other.Index, inv(other.Index^share.Index)))
+			}
+		}
+
+		for position, y := range share.Value {
+			secret[position] ^= mul(y, basis)
+		}
+	}
+
+	return secret, nil
+}
+
+// Bytes encodes the share as its split identifier, threshold, index, value and a 4-byte checksum.
+func (s Share) Bytes() []byte {
+	encoded := make([]byte, 0, headerSize+len(s.Value)+checksumSize)
+	encoded = append(encoded, s.ID[:]...)
+	encoded = append(encoded, s.Threshold, s.Index)
+	encoded = append(encoded, s.Value...)
+
+	return append(encoded, checksum(encoded)...)
+}
+
+// Parse decodes a share encoded by Bytes, verifying its checksum, threshold and index.
+func Parse(encoded []byte) (Share, error) {
+	if len(encoded) <= headerSize+checksumSize {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return Share{}, fmt.Errorf("share is too short: %d bytes", len(encoded))
+	}
+
+	content, sum := encoded[:len(encoded)-checksumSize], encoded[len(encoded)-checksumSize:]
+
+	if subtle.ConstantTimeCompare(checksum(content), sum) != 1 {
+		return Share{}, ErrChecksum
+	}
+
+	share := Share{
+		ID:        [IDSize]byte(content[:IDSize]),
+		Threshold: content[IDSize],
+		Index:     content[IDSize+1],
+		Value:     bytes.Clone(content[headerSize:]),
+	}
+
+	switch {
+	case share.Threshold < 2:
+		return Share{}, fmt.Errorf("%w: threshold must be at least 2, got %d", ErrShares, share.Threshold)
+	case share.Index == 0:
+		return Share{}, fmt.Errorf("%w: share index must not be 0", ErrShares)
+	}
+
+	return share, nil
+}
+
+// checksum returns the truncated SHA-256 digest of the content.
+func checksum(content []byte) []byte {
+	sum := sha256.Sum256(content)
+
+	return sum[:checksumSize]
+}
@@ -0,0 +1,173 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// TestMul checks multiplication in GF(2^8) against the examples of FIPS 197.
+func TestMul(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b, want byte
+	}{
+		{0x57, 0x83, 0xc1},
+		{0x57, 0x13, 0xfe},
+		{0x53, 0xca, 0x01},
+		{0x00, 0xff, 0x00},
+		{0x01, 0xab, 0xab},
+	}
+
+	for _, test := range tests {
+		if got := mul(test.a, test.b); got != test.want {
+			t.Errorf("mul(%#02x, %#02x) = %#02x, want %#02x", test.a, test.b, got, test.want)
+		}
+
+		if got := mul(test.b, test.a); got != test.want {
+			t.Errorf("mul(%#02x, %#02x) = %#02x, want %#02x", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+// TestInv checks that every non-zero element times its inverse is one.
+func TestInv(t *testing.T) {
+	t.Parallel()
+
+	for a := 1; a < 256; a++ {
+		if got := mul(byte(a), inv(byte(a))); got != 1 {
+			t.Errorf("mul(%#02x, inv(%#02x)) = %#02x, want 0x01", a, a, got)
+		}
+	}
+}
+
+// TestCombineKnownShares recovers "gogen" from shares of known polynomials of degree 2,
+// computed independently of this package.
+func TestCombineKnownShares(t *testing.T) {
+	t.Parallel()
+
+	values := map[byte]string{
+		1: "6351770b0a",
+		2: "115b8d4500",
+		3: "15659d2b64",
+		4: "683cadf81c",
+		5: "6c02bd9678",
+	}
+
+	subsets := [][]byte{{1, 2, 3}, {1, 3, 5}, {5, 4, 2}, {2, 3, 4, 5}}
+
+	for _, subset := range subsets {
+		shares := make([]Share, 0, len(subset))
+
+		for _, index := range subset {
+			value, err := hex.DecodeString(values[index])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			shares = append(shares, Share{ID: [IDSize]byte{1}, Threshold: 3, Index: index, Value: value})
+		}
+
+		secret, err := Combine(shares)
+		if err != nil {
+			t.Fatalf("shares %v: %v", subset, err)
+		}
+
+		if string(secret) != "gogen" {
+			t.Errorf("shares %v: secret = %q, want %q", subset, secret, "gogen")
+		}
+	}
+}
+
+// TestSplitCombine recovers secrets from every subset of threshold shares.
+func TestSplitCombine(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("correct horse battery staple")
+
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range shares {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				recovered, err := Combine([]Share{shares[k], shares[i], shares[j]})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(recovered, secret) {
+					t.Errorf("shares %d, %d, %d: secret = %q", i, j, k, recovered)
+				}
+			}
+		}
+	}
+
+	if _, err := Combine(shares[:2]); !errors.Is(err, ErrShares) {
+		t.Errorf("combining too few shares: error = %v, want %v", err, ErrShares)
+	}
+}
+
+// TestCombineDifferentSplits rejects shares of different splits of the same length and threshold.
+func TestCombineDifferentSplits(t *testing.T) {
+	t.Parallel()
+
+	first, err := Split([]byte("first secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := Split([]byte("other secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Combine([]Share{first[0], second[1]}); !errors.Is(err, ErrShares) {
+		t.Errorf("error = %v, want %v", err, ErrShares)
+	}
+}
+
+// TestEncoding checks the encoding of a share against a known answer, and the errors of Parse.
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+
+	share := Share{ID: [IDSize]byte{1, 2, 3, 4}, Threshold: 2, Index: 1, Value: []byte{0x45}}
+
+	const want = "01020304020145910f420b"
+
+	encoded := share.Bytes()
+	if got := hex.EncodeToString(encoded); got != want {
+		t.Fatalf("Bytes() = %s, want %s", got, want)
+	}
+
+	parsed, err := Parse(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.ID != share.ID || parsed.Threshold != share.Threshold || parsed.Index != share.Index ||
+		!bytes.Equal(parsed.Value, share.Value) {
+		t.Errorf("Parse() = %+v, want %+v", parsed, share)
+	}
+
+	corrupted := bytes.Clone(encoded)
+	corrupted[len(corrupted)-5] ^= 1
+
+	if _, err := Parse(corrupted); !errors.Is(err, ErrChecksum) {
+		t.Errorf("corrupted share: error = %v, want %v", err, ErrChecksum)
+	}
+
+	for _, invalid := range []Share{
+		{Threshold: 1, Index: 1, Value: []byte{1}},
+		{Threshold: 0, Index: 1, Value: []byte{1}},
+		{Threshold: 2, Index: 0, Value: []byte{1}},
+	} {
+		if _, err := Parse(invalid.Bytes()); !errors.Is(err, ErrShares) {
+			t.Errorf("share %+v: error = %v, want %v", invalid, err, ErrShares)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package signature provides HMAC signing and constant-time verification of payloads,
+// including the webhook signature header formats of GitHub and Stripe.
+//
+// Example usage:
+//
+//	mac, err := signature.MAC(key, payload, signature.SHA256)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	header := signature.GitHub(mac, signature.SHA256) // "sha256=..."
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMismatch indicates a signature that does not match the payload.
+var ErrMismatch = errors.New("signature mismatch")
+
+// ErrHeader indicates a malformed signature header.
+var ErrHeader = errors.New("malformed signature header")
+
+// Algorithm is the hash function of an HMAC.
+type Algorithm string
+
+const (
+	// SHA256 is HMAC-SHA256.
+	SHA256 Algorithm = "sha256"
+	// SHA384 is HMAC-SHA384.
+	SHA384 Algorithm = "sha384"
+	// SHA512 is HMAC-SHA512.
+	SHA512 Algorithm = "sha512"
+)
+
+// hash returns the constructor of the hash function of the algorithm.
+func (a Algorithm) hash() (func() hash.Hash, error) {
+	switch a {
+	case SHA256:
+		return sha256.New, nil
+	case SHA384:
+		return sha512.New384, nil
+	case SHA512:
+		return sha512.New, nil
+	default:
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("unsupported algorithm %q", a)
+	}
+}
+
+// MAC computes the HMAC of everything read from the reader.
+// The payload is streamed, so arbitrarily large payloads can be signed.
+func MAC(key []byte, payload io.Reader, algorithm Algorithm) ([]byte, error) {
+	constructor, err := algorithm.hash()
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(constructor, key)
+
+	if _, err := io.Copy(mac, payload); err != nil {
+		return nil, fmt.Errorf("reading payload: %w", err)
+	}
+
+	return mac.Sum(nil), nil
+}
+
+// Verify compares the expected and computed MACs in constant time.
+// It returns ErrMismatch if they differ.
+func Verify(expected, computed []byte) error {
+	if !hmac.Equal(expected, computed) {
+		return ErrMismatch
+	}
+
+	return nil
+}
+
+// GitHub formats a MAC as the value of GitHub's X-Hub-Signature-256 header ("sha256=<hex>").
+func GitHub(mac []byte, algorithm Algorithm) string {
+	return string(algorithm) + "=" + hex.EncodeToString(mac)
+}
+
+// ParseGitHub parses a GitHub-style signature header ("<algorithm>=<hex>").
+func ParseGitHub(header string) (Algorithm, []byte, error) {
+	name, encoded, found := strings.Cut(strings.TrimSpace(header), "=")
+	if !found {
+		return "", nil, fmt.Errorf("%w: expected <algorithm>=<hex>", ErrHeader)
+	}
+
+	algorithm := Algorithm(strings.ToLower(name))
+	if _, err := algorithm.hash(); err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrHeader, err)
+	}
+
+	mac, err := hex.DecodeString(encoded)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: decoding signature: %w", ErrHeader, err)
+	}
+
+	return algorithm, mac, nil
+}
+
+// Stripe is a Stripe-style signature header ("t=<timestamp>,v1=<hex>[,v1=<hex>...]").
+// The signed payload is the timestamp, a dot and the body, signed with HMAC-SHA256.
+type Stripe struct {
+	// Timestamp is the Unix time the payload was signed at
+	Timestamp int64
+
+	// Signatures are the v1 signatures; verification succeeds if any of them matches
+	Signatures [][]byte
+}
+
+// String formats the header.
+func (s Stripe) String() string {
+	parts := []string{"t=" + strconv.FormatInt(s.Timestamp, 10)}
+
+	for _, signature := range s.Signatures {
+		parts = append(parts, "v1="+hex.EncodeToString(signature))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Payload returns the signed payload of the body at the timestamp of the header.
+func (s Stripe) Payload(body io.Reader) io.Reader {
+	return io.MultiReader(strings.NewReader(strconv.FormatInt(s.Timestamp, 10)+"."), body)
+}
+
+// Verify checks that any of the signatures matches the computed MAC of the payload and,
+// unless the tolerance is 0, that the timestamp is within the tolerance of now.
+func (s Stripe) Verify(computed []byte, tolerance time.Duration, now time.Time) error {
+	if tolerance > 0 {
+		if age := now.Sub(time.Unix(s.Timestamp, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp is %s away from now, outside the tolerance of %s",
+				ErrMismatch, age.Round(time.Second), tolerance)
+		}
+	}
+
+	for _, signature := range s.Signatures {
+		if hmac.Equal(signature, computed) {
+			return nil
+		}
+	}
+
+	return ErrMismatch
+}
+
+// ParseStripe parses a Stripe-style signature header.
+// Schemes other than v1 (e.g. the v0 test scheme) are ignored.
+func ParseStripe(header string) (Stripe, error) {
+	var (
+		stripe    Stripe
+		timestamp bool
+	)
+
+	for part := range strings.SplitSeq(strings.TrimSpace(header), ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return Stripe{}, fmt.Errorf("%w: expected key=value pairs, got %q", ErrHeader, part)
+		}
+
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return Stripe{}, fmt.Errorf("%w: parsing timestamp: %w", ErrHeader, err)
+			}
+
+			stripe.Timestamp, timestamp = parsed, true
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return Stripe{}, fmt.Errorf("%w: decoding signature: %w", ErrHeader, err)
+			}
+
+			stripe.Signatures = append(stripe.Signatures, signature)
+		}
+	}
+
+	if !timestamp || len(stripe.Signatures) == 0 {
+		return Stripe{}, fmt.Errorf("%w: expected t=<timestamp>,v1=<hex>", ErrHeader)
+	}
+
+	return stripe, nil
+}
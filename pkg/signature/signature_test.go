@@ -0,0 +1,136 @@
+package signature_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/idelchi/gogen/pkg/signature"
+)
+
+// mac computes the MAC of the payload.
+func mac(t *testing.T, key, payload string, algorithm signature.Algorithm) []byte {
+	t.Helper()
+
+	sum, err := signature.MAC([]byte(key), strings.NewReader(payload), algorithm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sum
+}
+
+// TestMAC checks the MACs against test case 2 of RFC 4231.
+func TestMAC(t *testing.T) {
+	t.Parallel()
+
+	tests := map[signature.Algorithm]string{
+		signature.SHA256: "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		signature.SHA384: "af45d2e376484031617f78d2b58a6b1b9c7ef464f5a01b47e42ec3736322445e" +
+			"8e2240ca5e69e2c78b3239ecfab21649",
+		signature.SHA512: "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea250554" +
+			"9758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737",
+	}
+
+	for algorithm, want := range tests {
+		if got := hex.EncodeToString(mac(t, "Jefe", "what do ya want for nothing?", algorithm)); got != want {
+			t.Errorf("%s: MAC = %s, want %s", algorithm, got, want)
+		}
+	}
+
+	if _, err := signature.MAC([]byte("Jefe"), strings.NewReader(""), "md5"); err == nil {
+		t.Error("unsupported algorithm succeeded")
+	}
+}
+
+// TestGitHub checks the example of GitHub's documentation on validating webhook deliveries.
+func TestGitHub(t *testing.T) {
+	t.Parallel()
+
+	const want = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	computed := mac(t, "It's a Secret to Everybody", "Hello, World!", signature.SHA256)
+
+	if header := signature.GitHub(computed, signature.SHA256); header != want {
+		t.Errorf("GitHub() = %s, want %s", header, want)
+	}
+
+	algorithm, expected, err := signature.ParseGitHub(" SHA256=" + strings.TrimPrefix(want, "sha256=") + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if algorithm != signature.SHA256 {
+		t.Errorf("algorithm = %s, want %s", algorithm, signature.SHA256)
+	}
+
+	if err := signature.Verify(expected, computed); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	tampered := mac(t, "It's a Secret to Everybody", "Hello, World?", signature.SHA256)
+	if err := signature.Verify(expected, tampered); !errors.Is(err, signature.ErrMismatch) {
+		t.Errorf("tampered payload: error = %v, want %v", err, signature.ErrMismatch)
+	}
+
+	for _, header := range []string{"757107ea", "md5=757107ea", "sha256=zz"} {
+		if _, _, err := signature.ParseGitHub(header); !errors.Is(err, signature.ErrHeader) {
+			t.Errorf("ParseGitHub(%q): error = %v, want %v", header, err, signature.ErrHeader)
+		}
+	}
+}
+
+// TestStripe signs and verifies a Stripe-style header, checking the signed payload and the tolerance.
+func TestStripe(t *testing.T) {
+	t.Parallel()
+
+	const (
+		body = `{"id":"evt_1"}`
+		want = "t=1700000000,v1=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925"
+	)
+
+	stripe := signature.Stripe{Timestamp: 1700000000}
+
+	computed, err := signature.MAC([]byte("whsec_test"), stripe.Payload(strings.NewReader(body)), signature.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stripe.Signatures = [][]byte{computed}
+
+	if header := stripe.String(); header != want {
+		t.Errorf("String() = %s, want %s", header, want)
+	}
+
+	// Additional schemes are ignored, and any of several v1 signatures may match.
+	parsed, err := signature.ParseStripe("t=1700000000,v0=00,v1=" + strings.Repeat("00", 32) + "," + want[len("t=1700000000,"):])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+
+	if err := parsed.Verify(computed, 5*time.Minute, now.Add(4*time.Minute)); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	if err := parsed.Verify(computed, 5*time.Minute, now.Add(-6*time.Minute)); !errors.Is(err, signature.ErrMismatch) {
+		t.Errorf("outside the tolerance: error = %v, want %v", err, signature.ErrMismatch)
+	}
+
+	if err := parsed.Verify(computed, 0, now.Add(24*time.Hour)); err != nil {
+		t.Errorf("without a tolerance: Verify() = %v", err)
+	}
+
+	if err := parsed.Verify(computed[1:], 0, now); !errors.Is(err, signature.ErrMismatch) {
+		t.Errorf("wrong MAC: error = %v, want %v", err, signature.ErrMismatch)
+	}
+
+	for _, header := range []string{"v1=00", "t=1700000000", "t=x,v1=00", "t=1700000000,v1=zz", "t=1700000000;v1=00"} {
+		if _, err := signature.ParseStripe(header); !errors.Is(err, signature.ErrHeader) {
+			t.Errorf("ParseStripe(%q): error = %v, want %v", header, err, signature.ErrHeader)
+		}
+	}
+}
@@ -2,9 +2,13 @@
 package stdin
 
 import (
+	"bufio"
+	"fmt"
 	"io"
 	"os"
 	"strings"
+
+	"golang.org/x/term"
 )
 
 // IsPiped checks if something has been piped to stdin.
@@ -20,3 +24,31 @@ func Read() (string, error) {
 
 	return strings.TrimSuffix(string(bytes), "\n"), err
 }
+
+// Secret reads a secret without echoing it.
+// On a terminal, the prompt is written to stderr and the input is hidden;
+// otherwise the first line of stdin is read, so secrets can be piped in.
+func Secret(prompt string) (string, error) {
+	descriptor := int(os.Stdin.Fd()) //nolint:gosec	// File descriptors fit into an int.
+
+	if !term.IsTerminal(descriptor) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("reading secret: %w", err)
+		}
+
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+
+	secret, err := term.ReadPassword(descriptor)
+
+	fmt.Fprintln(os.Stderr)
+
+	if err != nil {
+		return "", fmt.Errorf("reading secret: %w", err)
+	}
+
+	return string(secret), nil
+}
@@ -0,0 +1,138 @@
+// Package strength estimates the strength of passwords and the time needed to crack them.
+//
+// Generated passwords are assessed by their entropy, while arbitrary passwords are scored
+// with a zxcvbn estimator, which detects dictionary words, keyboard walks, dates, sequences,
+// repetitions and l33t substitutions using embedded data.
+//
+// Example usage:
+//
+//	estimate := strength.Check("correcthorsebatterystaple", nil)
+//
+//	for _, rate := range strength.DefaultRates() {
+//	    fmt.Println(rate.Name, strength.Humanize(rate.CrackTime(estimate.Entropy)))
+//	}
+package strength
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nbutton23/zxcvbn-go"
+)
+
+// Rate is a named number of guesses an attacker can perform per second.
+type Rate struct {
+	// Name describes the attack scenario
+	Name string
+
+	// PerSecond is the number of guesses per second
+	PerSecond float64
+}
+
+// DefaultRates returns the attack scenarios used by zxcvbn.
+func DefaultRates() []Rate {
+	const hour = 3600
+
+	return []Rate{
+		{Name: "online, throttled", PerSecond: 100.0 / hour},
+		{Name: "online, unthrottled", PerSecond: 10},
+		{Name: "offline, slow hash", PerSecond: 1e4},
+		{Name: "offline, fast hash", PerSecond: 1e10},
+	}
+}
+
+// CrackTime returns the average time in seconds needed to guess a password with the given entropy,
+// i.e. the time to search half of the key space.
+func (r Rate) CrackTime(entropy float64) float64 {
+	return math.Pow(2, entropy-1) / r.PerSecond
+}
+
+// Humanize formats a duration in seconds in a human-readable way, e.g. "3 days" or "centuries".
+func Humanize(seconds float64) string {
+	const (
+		minute  = 60
+		hour    = 60 * minute
+		day     = 24 * hour
+		month   = 31 * day
+		year    = 12 * month
+		century = 100 * year
+	)
+
+	units := []struct {
+		name    string
+		seconds float64
+	}{
+		{"year", year},
+		{"month", month},
+		{"day", day},
+		{"hour", hour},
+		{"minute", minute},
+		{"second", 1},
+	}
+
+	switch {
+	case seconds < 1:
+		return "less than a second"
+	case seconds >= century:
+		return "centuries"
+	}
+
+	for _, unit := range units {
+		if seconds >= unit.seconds {
+			count := int(math.Round(seconds / unit.seconds))
+			if count == 1 {
+				return "1 " + unit.name
+			}
+
+			return fmt.Sprintf("%d %ss", count, unit.name)
+		}
+	}
+
+	return "less than a second"
+}
+
+// Match is a weakness found in a password, e.g. a dictionary word or a keyboard walk.
+type Match struct {
+	// Pattern is the kind of weakness (dictionary, spatial, date, sequence, repeat, bruteforce)
+	Pattern string
+
+	// Token is the part of the password that matched
+	Token string
+
+	// Dictionary is the name of the dictionary or keyboard layout that matched, if any
+	Dictionary string
+}
+
+// Estimate is the result of scoring a password.
+type Estimate struct {
+	// Score rates the password from 0 (too guessable) to 4 (very unguessable)
+	Score int
+
+	// Entropy is the estimated entropy in bits
+	Entropy float64
+
+	// Matches is the sequence of weaknesses the estimate is based on
+	Matches []Match
+}
+
+// Check scores the password with the zxcvbn estimator.
+// The user inputs, e.g. names or email addresses, are treated as additional dictionary words.
+func Check(password string, userInputs []string) Estimate {
+	result := zxcvbn.PasswordStrength(password, userInputs)
+
+	matches := make([]Match, len(result.MatchSequence))
+
+	for index, match := range result.MatchSequence {
+		matches[index] = Match{
+			Pattern:    match.Pattern,
+			Token:      match.Token,
+			Dictionary: match.DictionaryName,
+		}
+	}
+
+	return Estimate{
+		Score:   result.Score,
+		Entropy: result.Entropy,
+		Matches: matches,
+	}
+}
@@ -0,0 +1,50 @@
+package uid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// base62 is the alphabet used by KSUIDs.
+	base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// ksuidEpoch is the KSUID epoch (2014-05-13T16:53:20Z) in Unix seconds.
+	ksuidEpoch = 1400000000
+)
+
+// KSUID generates a K-Sortable Unique Identifier: a 32-bit timestamp in seconds since the KSUID epoch
+// followed by 128 random bits, encoded as 27 base62 characters.
+func KSUID(at time.Time) (string, error) {
+	var id [20]byte
+
+	binary.BigEndian.PutUint32(id[:4], uint32(at.Unix()-ksuidEpoch)) //nolint:gosec	// Valid until 2150.
+
+	if _, err := rand.Read(id[4:]); err != nil {
+		return "", fmt.Errorf("generating ksuid: %w", err)
+	}
+
+	const length = 27
+
+	value := new(big.Int).SetBytes(id[:])
+	radix := big.NewInt(int64(len(base62)))
+	digit := new(big.Int)
+
+	var encoded strings.Builder
+
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, digit)
+		encoded.WriteByte(base62[digit.Int64()])
+	}
+
+	reversed := []byte(encoded.String())
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+
+	return strings.Repeat("0", length-len(reversed)) + string(reversed), nil
+}
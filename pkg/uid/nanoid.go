@@ -0,0 +1,25 @@
+package uid
+
+import (
+	"fmt"
+
+	"github.com/idelchi/gogen/pkg/pw"
+)
+
+const (
+	// NanoIDAlphabet is the default URL-safe alphabet of NanoIDs.
+	NanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	// NanoIDSize is the default length of NanoIDs.
+	NanoIDSize = 21
+)
+
+// NanoID generates a random identifier of the given size from the alphabet.
+func NanoID(alphabet string, size int) (string, error) {
+	id, err := pw.GenerateFrom(size, []pw.Class{{Name: "alphabet", Chars: alphabet}})
+	if err != nil {
+		return "", fmt.Errorf("generating nanoid: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,78 @@
+package uid
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// SnowflakeEpoch is the default epoch of Snowflake IDs (2010-11-04T01:42:54.657Z), in Unix milliseconds.
+	SnowflakeEpoch = 1288834974657
+
+	// nodeBits is the number of bits of the node ID.
+	nodeBits = 10
+
+	// sequenceBits is the number of bits of the per-millisecond sequence number.
+	sequenceBits = 12
+
+	// MaxNode is the largest node ID.
+	MaxNode = 1<<nodeBits - 1
+)
+
+// Snowflake generates Snowflake-style IDs: a 41-bit millisecond timestamp since the epoch,
+// a 10-bit node ID and a 12-bit sequence number, formatted as a decimal integer.
+// IDs generated by the same Snowflake are unique and increasing.
+type Snowflake struct {
+	node  int64
+	epoch int64
+
+	mu       sync.Mutex
+	last     int64
+	sequence int64
+}
+
+// NewSnowflake returns a Snowflake generator for the node, counting milliseconds since the epoch.
+func NewSnowflake(node int, epoch int64) (*Snowflake, error) {
+	if node < 0 || node > MaxNode {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("node must be between 0 and %d, got %d", MaxNode, node)
+	}
+
+	return &Snowflake{node: int64(node), epoch: epoch}, nil
+}
+
+// Next returns the next ID.
+// When the sequence of the current millisecond is exhausted, it waits for the next millisecond.
+func (s *Snowflake) Next() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UnixMilli() - s.epoch
+	if now < 0 {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return "", fmt.Errorf("epoch %d is in the future", s.epoch)
+	}
+
+	// Never go back in time, e.g. when the clock is adjusted.
+	now = max(now, s.last)
+
+	if now == s.last {
+		s.sequence = (s.sequence + 1) & (1<<sequenceBits - 1)
+
+		if s.sequence == 0 {
+			for now <= s.last {
+				time.Sleep(time.Millisecond)
+
+				now = time.Now().UnixMilli() - s.epoch
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+
+	s.last = now
+
+	return strconv.FormatInt(now<<(nodeBits+sequenceBits)|s.node<<sequenceBits|s.sequence, 10), nil
+}
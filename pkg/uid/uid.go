@@ -1,11 +1,12 @@
 // Package uid provides methods for generating unique identifiers.
+//
+// It supports UUIDs (random v4, time-ordered v7 and name-based v5 and v3), ULIDs, KSUIDs,
+// NanoIDs with custom alphabets and sizes, and Snowflake-style IDs.
 package uid
 
 import (
 	"crypto/sha512"
 	"encoding/hex"
-
-	"github.com/google/uuid"
 )
 
 // Hash takes a string as input and returns its sha512 hash in hexadecimal format.
@@ -18,10 +19,3 @@ func Hash(str string) string {
 	// Return the hexadecimal encoding of the hash.
 	return hex.EncodeToString(hash)
 }
-
-// UUID generates a new UUID and returns it as a string.
-func UUID() string {
-	id := uuid.New()
-
-	return id.String()
-}
@@ -0,0 +1,192 @@
+package uid_test
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/idelchi/gogen/pkg/uid"
+)
+
+// TestNameBased checks the name-based UUIDs against the examples of RFC 9562, appendix A.
+func TestNameBased(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		generate  func(namespace, name string) (string, error)
+		namespace string
+		want      string
+	}{
+		{"v5", uid.UUIDv5, "dns", "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{"v5 uppercase namespace", uid.UUIDv5, "DNS", "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{"v5 uuid namespace", uid.UUIDv5, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{"v3", uid.UUIDv3, "dns", "5df41881-3aed-3515-88a7-2f4a814cf09e"},
+	}
+
+	for _, test := range tests {
+		got, err := test.generate(test.namespace, "www.example.com")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+
+	if _, err := uid.UUIDv5("gogen", "www.example.com"); err == nil {
+		t.Error("unknown namespace succeeded")
+	}
+}
+
+// TestRandom checks the version and variant of random and time-ordered UUIDs.
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		generate func() (string, error)
+		version  uuid.Version
+	}{
+		{uid.UUIDv4, 4},
+		{uid.UUIDv7, 7},
+		{func() (string, error) { return uid.UUID(), nil }, 4},
+	}
+
+	for _, test := range tests {
+		generated, err := test.generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := uuid.Parse(generated)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id.Version() != test.version || id.Variant() != uuid.RFC4122 {
+			t.Errorf("%s: version %d, variant %s, want version %d", id, id.Version(), id.Variant(), test.version)
+		}
+	}
+}
+
+// TestULID checks the timestamp of a ULID against the example of the ULID specification.
+func TestULID(t *testing.T) {
+	t.Parallel()
+
+	id, err := uid.ULID(time.UnixMilli(1469918176385))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(id) != 26 || !strings.HasPrefix(id, "01ARYZ6S41") {
+		t.Errorf("ULID = %s, want 26 characters starting with 01ARYZ6S41", id)
+	}
+
+	const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	if strings.Trim(id, crockford) != "" {
+		t.Errorf("ULID %s contains characters outside of the Crockford base32 alphabet", id)
+	}
+}
+
+// TestKSUID decodes a KSUID and checks its timestamp.
+func TestKSUID(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC)
+
+	id, err := uid.KSUID(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	if len(id) != 27 {
+		t.Fatalf("KSUID %s has %d characters, want 27", id, len(id))
+	}
+
+	value := new(big.Int)
+
+	for _, char := range id {
+		digit := strings.IndexRune(base62, char)
+		if digit < 0 {
+			t.Fatalf("KSUID %s contains %q outside of the base62 alphabet", id, char)
+		}
+
+		value.Mul(value, big.NewInt(62))
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+
+	// The timestamp of the example KSUID 0ujtsYcgvSTl8PAuAdqWYSMnLOv of the KSUID specification.
+	const want = 107608047
+
+	if got := value.Rsh(value, 128).Int64(); got != want {
+		t.Errorf("KSUID timestamp = %d, want %d", got, want)
+	}
+}
+
+// TestNanoID checks the length and alphabet of NanoIDs.
+func TestNanoID(t *testing.T) {
+	t.Parallel()
+
+	id, err := uid.NanoID("ab", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(id) != 64 || strings.Trim(id, "ab") != "" {
+		t.Errorf("NanoID = %s, want 64 characters of ab", id)
+	}
+}
+
+// TestSnowflake decomposes Snowflake IDs into their timestamp, node and sequence.
+func TestSnowflake(t *testing.T) {
+	t.Parallel()
+
+	if _, err := uid.NewSnowflake(uid.MaxNode+1, uid.SnowflakeEpoch); err == nil {
+		t.Error("node beyond the maximum succeeded")
+	}
+
+	const node = 42
+
+	generator, err := uid.NewSnowflake(node, uid.SnowflakeEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().UnixMilli() - uid.SnowflakeEpoch
+
+	var previous int64
+
+	for range 5000 {
+		next, err := generator.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := strconv.ParseInt(next, 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id <= previous {
+			t.Fatalf("ID %d does not increase over %d", id, previous)
+		}
+
+		if got := id >> 12 & uid.MaxNode; got != node {
+			t.Fatalf("ID %d has node %d, want %d", id, got, node)
+		}
+
+		if timestamp := id >> 22; timestamp < before || timestamp > time.Now().UnixMilli()-uid.SnowflakeEpoch {
+			t.Fatalf("ID %d has timestamp %d outside of the generation time", id, timestamp)
+		}
+
+		previous = id
+	}
+}
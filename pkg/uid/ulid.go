@@ -0,0 +1,43 @@
+package uid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates a Universally Unique Lexicographically Sortable Identifier:
+// a 48-bit millisecond timestamp followed by 80 random bits, encoded as 26 Crockford base32 characters.
+func ULID(at time.Time) (string, error) {
+	var id [16]byte
+
+	var timestamp [8]byte
+
+	binary.BigEndian.PutUint64(timestamp[:], uint64(at.UnixMilli())) //nolint:gosec	// Timestamps after 1970.
+	copy(id[:6], timestamp[2:])
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		return "", fmt.Errorf("generating ulid: %w", err)
+	}
+
+	// 128 bits are encoded as 26 characters of 5 bits each, the first character carrying only 3 bits.
+	const length = 26
+
+	encoded := make([]byte, length)
+
+	high := binary.BigEndian.Uint64(id[:8])
+	low := binary.BigEndian.Uint64(id[8:])
+
+	for index := length - 1; index >= 0; index-- {
+		encoded[index] = crockford[low&0x1f]
+
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+
+	return string(encoded), nil
+}
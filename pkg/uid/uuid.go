@@ -0,0 +1,87 @@
+package uid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Namespaces are the predefined UUID namespaces of RFC 9562 for name-based UUIDs.
+//
+//nolint:gochecknoglobals	// Constant lookup table.
+var Namespaces = map[string]uuid.UUID{
+	"dns":  uuid.NameSpaceDNS,
+	"url":  uuid.NameSpaceURL,
+	"oid":  uuid.NameSpaceOID,
+	"x500": uuid.NameSpaceX500,
+}
+
+// UUIDv4 generates a random UUID (version 4).
+func UUIDv4() (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("generating uuid: %w", err)
+	}
+
+	return id.String(), nil
+}
+
+// UUID generates a new random UUID and returns it as a string.
+// It panics if the random source fails.
+//
+// Deprecated: Use UUIDv4, which returns the error instead.
+func UUID() string {
+	id, err := UUIDv4()
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// UUIDv7 generates a time-ordered UUID (version 7), which sorts by creation time.
+func UUIDv7() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("generating uuid: %w", err)
+	}
+
+	return id.String(), nil
+}
+
+// UUIDv5 generates the name-based UUID (version 5, SHA-1) of the name in the namespace.
+// The namespace is one of the keys of Namespaces or a UUID.
+func UUIDv5(namespace, name string) (string, error) {
+	space, err := Namespace(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	return uuid.NewSHA1(space, []byte(name)).String(), nil
+}
+
+// UUIDv3 generates the name-based UUID (version 3, MD5) of the name in the namespace.
+// The namespace is one of the keys of Namespaces or a UUID.
+func UUIDv3(namespace, name string) (string, error) {
+	space, err := Namespace(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	return uuid.NewMD5(space, []byte(name)).String(), nil
+}
+
+// Namespace resolves the name of a predefined namespace, or parses a UUID.
+func Namespace(namespace string) (uuid.UUID, error) {
+	if space, ok := Namespaces[strings.ToLower(namespace)]; ok {
+		return space, nil
+	}
+
+	space, err := uuid.Parse(namespace)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parsing namespace %q: %w", namespace, err)
+	}
+
+	return space, nil
+}
@@ -0,0 +1,95 @@
+// Package wg provides WireGuard key generation: Curve25519 private and public keys
+// and preshared keys, in the base64 format used by the wg tool and configuration files.
+//
+// Example usage:
+//
+//	private, err := wg.NewPrivateKey()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	public, err := private.Public()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fmt.Println(private, public)
+package wg
+
+import (
+	"crypto/ecdh"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/idelchi/gogen/pkg/key"
+)
+
+// KeySize is the size of WireGuard keys in bytes.
+const KeySize = 32
+
+// Key is a WireGuard key.
+type Key key.Key
+
+// String returns the key in base64, as used by wg and configuration files.
+func (k Key) String() string {
+	return key.Key(k).AsBase64()
+}
+
+// NewPrivateKey generates a random Curve25519 private key, clamped like wg genkey.
+func NewPrivateKey() (Key, error) {
+	private, err := key.New(KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("generating private key: %w", err)
+	}
+
+	private[0] &= 248
+	private[31] = (private[31] & 127) | 64
+
+	return Key(private), nil
+}
+
+// NewPresharedKey generates a random preshared key, like wg genpsk.
+func NewPresharedKey() (Key, error) {
+	psk, err := key.New(KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("generating preshared key: %w", err)
+	}
+
+	return Key(psk), nil
+}
+
+// ParseKey decodes a base64-encoded key.
+func ParseKey(encoded string) (Key, error) {
+	decoded, err := key.FromBase64(encoded)
+	if err != nil {
+		return nil, err //nolint:wrapcheck	// Error does not need additional wrapping.
+	}
+
+	if len(decoded) != KeySize {
+		//nolint:err113 // Occasional dynamic errors are fine.
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(decoded))
+	}
+
+	return Key(decoded), nil
+}
+
+// Public derives the public key of a private key, like wg pubkey.
+func (k Key) Public() (Key, error) {
+	public, err := curve25519.X25519(k, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("deriving public key: %w", err)
+	}
+
+	return Key(public), nil
+}
+
+// X25519 returns the public key as a standard library X25519 public key, e.g. to fingerprint it.
+func (k Key) X25519() (*ecdh.PublicKey, error) {
+	public, err := ecdh.X25519().NewPublicKey(k)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	return public, nil
+}
@@ -0,0 +1,91 @@
+package wg_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/idelchi/gogen/pkg/wg"
+)
+
+// TestPublic derives the public keys of the X25519 test vectors of RFC 7748, section 6.1.
+func TestPublic(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a": "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a",
+		"5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb": "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f",
+	}
+
+	for private, want := range tests {
+		decoded, err := hex.DecodeString(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Keys are exchanged in base64, like wg genkey | wg pubkey.
+		key, err := wg.ParseKey(base64.StdEncoding.EncodeToString(decoded))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		public, err := key.Public()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := hex.EncodeToString(public); got != want {
+			t.Errorf("Public(%s) = %s, want %s", private, got, want)
+		}
+
+		if _, err := public.X25519(); err != nil {
+			t.Errorf("X25519() = %v", err)
+		}
+	}
+}
+
+// TestNewPrivateKey checks the clamping of generated private keys and the base64 round trip.
+func TestNewPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		private, err := wg.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(private) != wg.KeySize || private[0]&7 != 0 || private[31]&128 != 0 || private[31]&64 == 0 {
+			t.Fatalf("private key %x is not clamped", []byte(private))
+		}
+
+		parsed, err := wg.ParseKey(private.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(parsed, private) {
+			t.Errorf("ParseKey(%s) = %x", private, []byte(parsed))
+		}
+	}
+
+	psk, err := wg.NewPresharedKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(psk) != wg.KeySize {
+		t.Errorf("preshared key of %d bytes, want %d", len(psk), wg.KeySize)
+	}
+}
+
+// TestParseKeyErrors rejects keys that are not 32 bytes of base64.
+func TestParseKeyErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, encoded := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(make([]byte, 31))} {
+		if _, err := wg.ParseKey(encoded); err == nil {
+			t.Errorf("ParseKey(%q) succeeded", encoded)
+		}
+	}
+}